@@ -1,6 +1,7 @@
 package onesignal
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"time"
@@ -89,6 +90,11 @@ type AppRequest struct {
 // List the apps.
 // https://documentation.onesignal.com/reference/view-apps-apps
 func (s *AppsService) List() ([]App, *http.Response, error) {
+	return s.ListContext(context.Background())
+}
+
+// ListContext lists the apps with the provided context.
+func (s *AppsService) ListContext(ctx context.Context) ([]App, *http.Response, error) {
 	// build the URL
 	u, err := url.Parse("/apps")
 	if err != nil {
@@ -96,7 +102,7 @@ func (s *AppsService) List() ([]App, *http.Response, error) {
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -114,6 +120,11 @@ func (s *AppsService) List() ([]App, *http.Response, error) {
 //
 // OneSignal API docs: https://documentation.onesignal.com/reference/view-an-app
 func (s *AppsService) Get(appID string) (*App, *http.Response, error) {
+	return s.GetContext(context.Background(), appID)
+}
+
+// GetContext gets a single app with the provided context.
+func (s *AppsService) GetContext(ctx context.Context, appID string) (*App, *http.Response, error) {
 	// build the URL
 	u, err := url.Parse("/apps/" + appID)
 	if err != nil {
@@ -121,7 +132,7 @@ func (s *AppsService) Get(appID string) (*App, *http.Response, error) {
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -139,6 +150,11 @@ func (s *AppsService) Get(appID string) (*App, *http.Response, error) {
 //
 // OneSignal API docs: https://documentation.onesignal.com/reference/create-an-app
 func (s *AppsService) Create(opt AppRequest) (*App, *http.Response, error) {
+	return s.CreateContext(context.Background(), opt)
+}
+
+// CreateContext creates an app with the provided context.
+func (s *AppsService) CreateContext(ctx context.Context, opt AppRequest) (*App, *http.Response, error) {
 	// build the URL
 	u, err := url.Parse("/apps")
 	if err != nil {
@@ -146,7 +162,7 @@ func (s *AppsService) Create(opt AppRequest) (*App, *http.Response, error) {
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), opt)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -164,6 +180,11 @@ func (s *AppsService) Create(opt AppRequest) (*App, *http.Response, error) {
 //
 // OneSignal API docs: https://documentation.onesignal.com/reference/update-an-app
 func (s *AppsService) Update(appID string, opt AppRequest) (*App, *http.Response, error) {
+	return s.UpdateContext(context.Background(), appID, opt)
+}
+
+// UpdateContext updates an app with the provided context.
+func (s *AppsService) UpdateContext(ctx context.Context, appID string, opt AppRequest) (*App, *http.Response, error) {
 	// build the URL
 	u, err := url.Parse("/apps/" + appID)
 	if err != nil {
@@ -171,7 +192,7 @@ func (s *AppsService) Update(appID string, opt AppRequest) (*App, *http.Response
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("PUT", u.String(), opt)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", u.String(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
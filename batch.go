@@ -0,0 +1,171 @@
+package onesignal
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultBatchConcurrency is the default number of requests sent at once by CreateBatched
+const defaultBatchConcurrency = 4
+
+// NotificationBatchOptions specifies the parameters to the
+// NotificationsService.CreateBatched method
+type NotificationBatchOptions struct {
+	// Maximum number of recipients per request. Defaults to, and is capped at, 2000.
+	ChunkSize int
+	// Maximum number of requests sent at once. Defaults to 4.
+	Concurrency int
+}
+
+// NotificationChunkResult is the result of the request sent for a chunk of recipients
+type NotificationChunkResult struct {
+	// The request sent for the chunk
+	Request *NotificationRequest
+	// The response of the request. Nil if the request failed.
+	Response *NotificationCreateResponse
+	// The error of the request, if any
+	Err error
+}
+
+// NotificationBatchResult aggregates the results of the NotificationsService.CreateBatched method
+type NotificationBatchResult struct {
+	// IDs of the created notifications
+	IDs []string
+	// Total number of recipients
+	Recipients int
+	// Recipient errors reported by all the chunks
+	Errors NotificationErrors
+	// Results of each chunk, in order
+	Chunks []NotificationChunkResult
+}
+
+// Err returns an error summarizing the failed chunks, or nil if every chunk succeeded
+func (r *NotificationBatchResult) Err() error {
+	var messages []string
+	for i, c := range r.Chunks {
+		if c.Err != nil {
+			messages = append(messages, fmt.Sprintf("chunk %d: %v", i, c.Err))
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d notification chunks failed:\n - %s", len(messages), len(r.Chunks), strings.Join(messages, "\n - "))
+}
+
+// CreateBatched creates notifications for a request whose include_player_ids or include_external_user_ids
+// exceed the API limit, by splitting the recipients in chunks sent with bounded concurrency.
+// The external_id of each chunk is derived from the external_id of the request, so the chunks stay idempotent.
+// The returned error is the error of the result, if any.
+func (s *NotificationsService) CreateBatched(ctx context.Context, opt *NotificationRequest, batchOpt ...NotificationBatchOptions) (*NotificationBatchResult, error) {
+	var bo NotificationBatchOptions
+	if len(batchOpt) > 0 {
+		bo = batchOpt[0]
+	}
+	if bo.ChunkSize <= 0 || bo.ChunkSize > maxIncludeRecipients {
+		bo.ChunkSize = maxIncludeRecipients
+	}
+	if bo.Concurrency <= 0 {
+		bo.Concurrency = defaultBatchConcurrency
+	}
+
+	requests := splitNotificationRequest(opt, bo.ChunkSize)
+	result := &NotificationBatchResult{
+		Chunks: make([]NotificationChunkResult, len(requests)),
+	}
+
+	sem := make(chan struct{}, bo.Concurrency)
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		result.Chunks[i].Request = req
+
+		select {
+		case <-ctx.Done():
+			result.Chunks[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(chunk *NotificationChunkResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			chunk.Response, _, chunk.Err = s.CreateContext(ctx, chunk.Request)
+		}(&result.Chunks[i])
+	}
+	wg.Wait()
+
+	responded := 0
+	notSubscribed := 0
+	for _, c := range result.Chunks {
+		if c.Response == nil {
+			continue
+		}
+		responded++
+		if c.Response.ID != "" {
+			result.IDs = append(result.IDs, c.Response.ID)
+		}
+		result.Recipients += c.Response.Recipients
+		if e := c.Response.Errors; e != nil {
+			result.Errors.Messages = append(result.Errors.Messages, e.Messages...)
+			result.Errors.InvalidPlayerIDs = append(result.Errors.InvalidPlayerIDs, e.InvalidPlayerIDs...)
+			result.Errors.InvalidExternalUserIDs = append(result.Errors.InvalidExternalUserIDs, e.InvalidExternalUserIDs...)
+			result.Errors.InvalidPhoneNumbers = append(result.Errors.InvalidPhoneNumbers, e.InvalidPhoneNumbers...)
+			result.Errors.InvalidEmailTokens = append(result.Errors.InvalidEmailTokens, e.InvalidEmailTokens...)
+			if e.AllPlayersNotSubscribed {
+				notSubscribed++
+			}
+		}
+	}
+	result.Errors.AllPlayersNotSubscribed = responded > 0 && notSubscribed == responded
+
+	return result, result.Err()
+}
+
+// splitNotificationRequest splits the player ids and external user ids of the request in chunks.
+// The request is returned as is when no split is needed.
+func splitNotificationRequest(opt *NotificationRequest, size int) []*NotificationRequest {
+	if len(opt.IncludePlayerIDs) <= size && len(opt.IncludeExternalUserIDs) <= size &&
+		(len(opt.IncludePlayerIDs) == 0 || len(opt.IncludeExternalUserIDs) == 0) {
+		return []*NotificationRequest{opt}
+	}
+
+	var requests []*NotificationRequest
+	for _, target := range []struct {
+		ids []string
+		set func(*NotificationRequest, []string)
+	}{
+		{opt.IncludePlayerIDs, func(r *NotificationRequest, ids []string) { r.IncludePlayerIDs = ids }},
+		{opt.IncludeExternalUserIDs, func(r *NotificationRequest, ids []string) { r.IncludeExternalUserIDs = ids }},
+	} {
+		for start := 0; start < len(target.ids); start += size {
+			end := start + size
+			if end > len(target.ids) {
+				end = len(target.ids)
+			}
+
+			req := *opt
+			req.IncludePlayerIDs = nil
+			req.IncludeExternalUserIDs = nil
+			target.set(&req, target.ids[start:end])
+			if opt.ExternalID != "" {
+				req.ExternalID = chunkExternalID(opt.ExternalID, len(requests))
+			}
+			requests = append(requests, &req)
+		}
+	}
+
+	return requests
+}
+
+// chunkExternalID derives a deterministic UUID for the chunk from the external id of the request
+func chunkExternalID(externalID string, index int) string {
+	h := sha1.Sum([]byte(fmt.Sprintf("%s/%d", externalID, index)))
+	h[6] = (h[6] & 0x0f) | 0x50 // version 5
+	h[8] = (h[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
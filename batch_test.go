@@ -0,0 +1,163 @@
+package onesignal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func fakeIDs(prefix string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = prefix + strconv.Itoa(i)
+	}
+	return ids
+}
+
+func TestNotificationsService_CreateBatched(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	var sizes []int
+	externalIDs := map[string]bool{}
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		req := &NotificationRequest{}
+		json.NewDecoder(r.Body).Decode(req)
+
+		mu.Lock()
+		inFlight--
+		size := len(req.IncludePlayerIDs) + len(req.IncludeExternalUserIDs)
+		sizes = append(sizes, size)
+		externalIDs[req.ExternalID] = true
+		mu.Unlock()
+
+		if len(req.IncludePlayerIDs) > 0 && len(req.IncludeExternalUserIDs) > 0 {
+			t.Errorf("Chunks should target a single kind of recipients")
+		}
+
+		resp := NotificationCreateResponse{
+			ID:         "notif-" + req.ExternalID,
+			Recipients: size,
+		}
+		if len(req.IncludePlayerIDs) > 0 && req.IncludePlayerIDs[0] == "player-0" {
+			resp.Errors = &NotificationErrors{InvalidPlayerIDs: []string{"player-0"}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	request := &NotificationRequest{
+		Contents:               map[string]string{"en": "English message"},
+		IncludePlayerIDs:       fakeIDs("player-", 4500),
+		IncludeExternalUserIDs: fakeIDs("user-", 10),
+		ExternalID:             "0f9b9a2e-3c1b-4b1e-9a5d-8b6a4f1e2d3c",
+	}
+
+	result, err := client.Notifications.CreateBatched(context.Background(), request, NotificationBatchOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("CreateBatched returned unexpected error: %v", err)
+	}
+
+	sort.Ints(sizes)
+	if want := []int{10, 500, 2000, 2000}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("Chunk sizes: %v, want %v", sizes, want)
+	}
+
+	if maxInFlight > 2 {
+		t.Errorf("Concurrent requests: %d, want at most %d", maxInFlight, 2)
+	}
+
+	if len(externalIDs) != 4 || externalIDs[request.ExternalID] {
+		t.Errorf("External IDs should be derived for each chunk: %v", externalIDs)
+	}
+
+	if got, want := result.Recipients, 4510; got != want {
+		t.Errorf("Recipients: %d, want %d", got, want)
+	}
+
+	if got, want := len(result.IDs), 4; got != want {
+		t.Errorf("IDs: %d, want %d", got, want)
+	}
+
+	if got, want := result.Errors.InvalidPlayerIDs, []string{"player-0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("InvalidPlayerIDs: %v, want %v", got, want)
+	}
+
+	if got := result.Chunks[0].Request.IncludePlayerIDs[0]; got != "player-0" {
+		t.Errorf("Chunks should be in order, first recipient is %v", got)
+	}
+}
+
+func TestNotificationsService_CreateBatched_chunkError(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		req := &NotificationRequest{}
+		json.NewDecoder(r.Body).Decode(req)
+		if req.IncludePlayerIDs[0] == "player-2" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":["Something went wrong"]}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"notif-fake-id","recipients":2}`)
+	})
+
+	result, err := client.Notifications.CreateBatched(context.Background(), &NotificationRequest{
+		Contents:         map[string]string{"en": "English message"},
+		IncludePlayerIDs: fakeIDs("player-", 6),
+	}, NotificationBatchOptions{ChunkSize: 2})
+	if err == nil {
+		t.Fatalf("CreateBatched should return an error")
+	}
+
+	if result.Chunks[1].Err == nil || result.Chunks[0].Err != nil || result.Chunks[2].Err != nil {
+		t.Errorf("Only the second chunk should fail: %+v", result.Chunks)
+	}
+
+	if got, want := result.Recipients, 4; got != want {
+		t.Errorf("Recipients: %d, want %d", got, want)
+	}
+}
+
+func TestSplitNotificationRequest_noSplit(t *testing.T) {
+	request := &NotificationRequest{IncludePlayerIDs: fakeIDs("player-", 10), ExternalID: "id"}
+
+	requests := splitNotificationRequest(request, maxIncludeRecipients)
+	if len(requests) != 1 || requests[0] != request {
+		t.Errorf("Request shouldn't be split: %+v", requests)
+	}
+}
+
+func TestChunkExternalID(t *testing.T) {
+	id := chunkExternalID("0f9b9a2e-3c1b-4b1e-9a5d-8b6a4f1e2d3c", 1)
+	if id != chunkExternalID("0f9b9a2e-3c1b-4b1e-9a5d-8b6a4f1e2d3c", 1) {
+		t.Errorf("chunkExternalID should be deterministic")
+	}
+	if id == chunkExternalID("0f9b9a2e-3c1b-4b1e-9a5d-8b6a4f1e2d3c", 2) {
+		t.Errorf("chunkExternalID should differ between chunks")
+	}
+	if len(id) != 36 || id[14] != '5' {
+		t.Errorf("chunkExternalID should be a version 5 UUID: %s", id)
+	}
+}
@@ -0,0 +1,573 @@
+package onesignal
+
+import (
+	"time"
+)
+
+// sendAfterLayout is the time format of the send_after parameter
+const sendAfterLayout = "2006-01-02 15:04:05 GMT-0700"
+
+// NotificationBuilder builds a NotificationRequest with a fluent API.
+// Platform specific fields are set in the IOS, Android, Huawei, Amazon and Web callbacks.
+//
+//	req, err := onesignal.NewPush().
+//		Heading("en", "Hello").
+//		Content("en", "World").
+//		ToSegments("Subscribed Users").
+//		IOS(func(b *onesignal.IOSBuilder) { b.Sound("ping.aiff").Badge(onesignal.IOSBadgeTypeIncrease, 1) }).
+//		Android(func(b *onesignal.AndroidBuilder) { b.ChannelID("news") }).
+//		Build()
+type NotificationBuilder struct {
+	channel MessageType
+	req     NotificationRequest
+}
+
+// NewPush returns a builder of push notifications
+func NewPush() *NotificationBuilder {
+	return &NotificationBuilder{channel: MessageTypePush}
+}
+
+// NewEmail returns a builder of emails. The body must include the [unsubscribe_url] placeholder.
+func NewEmail(subject, body string) *NotificationBuilder {
+	b := &NotificationBuilder{channel: MessageTypeEmail}
+	b.req.EmailSubject = subject
+	b.req.EmailBody = body
+	return b
+}
+
+// NewSMS returns a builder of SMS with the English content
+func NewSMS(content string) *NotificationBuilder {
+	b := &NotificationBuilder{channel: MessageTypeSMS}
+	return b.Content("en", content)
+}
+
+// Build returns the notification request, with ChannelForExternalUserIDs set when targeting external user ids.
+// The request is returned along with the ValidationErrors if it isn't valid.
+// It doesn't share its maps and slices with the builder, which can be reused to build other requests.
+func (b *NotificationBuilder) Build() (*NotificationRequest, error) {
+	req := b.req.clone()
+	if len(req.IncludeExternalUserIDs) > 0 {
+		req.ChannelForExternalUserIDs = b.channel
+	}
+	return &req, req.Validate()
+}
+
+// Name sets the name used to identify the message in the dashboard
+func (b *NotificationBuilder) Name(name string) *NotificationBuilder {
+	b.req.Name = name
+	return b
+}
+
+// Content sets the content of the message in a language
+func (b *NotificationBuilder) Content(language, text string) *NotificationBuilder {
+	b.req.Contents = setText(b.req.Contents, language, text)
+	return b
+}
+
+// Heading sets the title of the notification in a language
+func (b *NotificationBuilder) Heading(language, text string) *NotificationBuilder {
+	b.req.Headings = setText(b.req.Headings, language, text)
+	return b
+}
+
+// Subtitle sets the subtitle of the notification in a language
+func (b *NotificationBuilder) Subtitle(language, text string) *NotificationBuilder {
+	b.req.Subtitle = setText(b.req.Subtitle, language, text)
+	return b
+}
+
+// Template uses a template set up on the dashboard
+func (b *NotificationBuilder) Template(templateID string) *NotificationBuilder {
+	b.req.TemplateID = templateID
+	return b
+}
+
+// CustomData sets the data substituted in the template
+func (b *NotificationBuilder) CustomData(data map[string]interface{}) *NotificationBuilder {
+	b.req.CustomData = data
+	return b
+}
+
+// ToSegments targets the users of segments
+func (b *NotificationBuilder) ToSegments(segments ...string) *NotificationBuilder {
+	b.req.IncludedSegments = append(b.req.IncludedSegments, segments...)
+	return b
+}
+
+// ExcludeSegments excludes the users of segments from the targeted segments
+func (b *NotificationBuilder) ExcludeSegments(segments ...string) *NotificationBuilder {
+	b.req.ExcludedSegments = append(b.req.ExcludedSegments, segments...)
+	return b
+}
+
+// ToFilters targets the users matching filters
+func (b *NotificationBuilder) ToFilters(filters ...Filter) *NotificationBuilder {
+	current, _ := b.req.Filters.(Filters)
+	b.req.Filters = append(current, filters...)
+	return b
+}
+
+// ToPlayers targets devices by player id
+func (b *NotificationBuilder) ToPlayers(ids ...string) *NotificationBuilder {
+	b.req.IncludePlayerIDs = append(b.req.IncludePlayerIDs, ids...)
+	return b
+}
+
+// ToExternalUsers targets users by external user id, on the channel of the builder
+func (b *NotificationBuilder) ToExternalUsers(ids ...string) *NotificationBuilder {
+	b.req.IncludeExternalUserIDs = append(b.req.IncludeExternalUserIDs, ids...)
+	return b
+}
+
+// ToEmails targets email addresses
+func (b *NotificationBuilder) ToEmails(emails ...string) *NotificationBuilder {
+	b.req.IncludeEmailTokens = append(b.req.IncludeEmailTokens, emails...)
+	return b
+}
+
+// ToPhoneNumbers targets phone numbers in E.164 format
+func (b *NotificationBuilder) ToPhoneNumbers(numbers ...string) *NotificationBuilder {
+	b.req.IncludePhoneNumber = append(b.req.IncludePhoneNumber, numbers...)
+	return b
+}
+
+// Data sets the custom data passed back to the app
+func (b *NotificationBuilder) Data(data interface{}) *NotificationBuilder {
+	b.req.Data = data
+	return b
+}
+
+// URL sets the URL opened when the notification is clicked
+func (b *NotificationBuilder) URL(url string) *NotificationBuilder {
+	b.req.URL = url
+	return b
+}
+
+// Button adds an action button to the notification
+func (b *NotificationBuilder) Button(button NotificationButton) *NotificationBuilder {
+	b.req.Buttons = append(b.req.Buttons, button)
+	return b
+}
+
+// SendAfter schedules the delivery of the message
+func (b *NotificationBuilder) SendAfter(t time.Time) *NotificationBuilder {
+	b.req.SendAfter = t.Format(sendAfterLayout)
+	return b
+}
+
+// DeliverInTimezone delivers the message at a time of day, like "9:00AM", in the timezone of each user
+func (b *NotificationBuilder) DeliverInTimezone(timeOfDay string) *NotificationBuilder {
+	b.req.DelayedOption = DelayedOptionTimezone
+	b.req.DeliveryTimeOfDay = timeOfDay
+	return b
+}
+
+// DeliverLastActive delivers the message at the time of day each user last used the app
+func (b *NotificationBuilder) DeliverLastActive() *NotificationBuilder {
+	b.req.DelayedOption = DelayedOptionLastActive
+	return b
+}
+
+// TTL sets the time to live of the notification
+func (b *NotificationBuilder) TTL(ttl time.Duration) *NotificationBuilder {
+	b.req.TTL = uint(ttl / time.Second)
+	return b
+}
+
+// Priority sets the delivery priority of the notification. 10 is high priority.
+func (b *NotificationBuilder) Priority(priority uint) *NotificationBuilder {
+	b.req.Priority = priority
+	return b
+}
+
+// CollapseID replaces the notifications with the same collapse id on the device
+func (b *NotificationBuilder) CollapseID(id string) *NotificationBuilder {
+	b.req.CollapseID = id
+	return b
+}
+
+// ExternalID sets the idempotency key of the request
+func (b *NotificationBuilder) ExternalID(id string) *NotificationBuilder {
+	b.req.ExternalID = id
+	return b
+}
+
+// EmailFrom sets the sender of the email
+func (b *NotificationBuilder) EmailFrom(name, address string) *NotificationBuilder {
+	b.req.EmailFromName = name
+	b.req.EmailFromAddress = address
+	return b
+}
+
+// SMSFrom sets the phone number sending the SMS
+func (b *NotificationBuilder) SMSFrom(from string) *NotificationBuilder {
+	b.req.SMSFrom = from
+	return b
+}
+
+// SMSMedia attaches media files to the SMS
+func (b *NotificationBuilder) SMSMedia(urls ...string) *NotificationBuilder {
+	b.req.SMSMediaURLs = append(b.req.SMSMediaURLs, urls...)
+	return b
+}
+
+// IOS sets the iOS specific fields
+func (b *NotificationBuilder) IOS(fn func(*IOSBuilder)) *NotificationBuilder {
+	fn(&IOSBuilder{req: &b.req})
+	return b
+}
+
+// Android sets the Android specific fields
+func (b *NotificationBuilder) Android(fn func(*AndroidBuilder)) *NotificationBuilder {
+	fn(&AndroidBuilder{req: &b.req})
+	return b
+}
+
+// Huawei sets the Huawei specific fields
+func (b *NotificationBuilder) Huawei(fn func(*HuaweiBuilder)) *NotificationBuilder {
+	fn(&HuaweiBuilder{req: &b.req})
+	return b
+}
+
+// Amazon sets the Amazon (ADM) specific fields
+func (b *NotificationBuilder) Amazon(fn func(*AmazonBuilder)) *NotificationBuilder {
+	fn(&AmazonBuilder{req: &b.req})
+	return b
+}
+
+// Web sets the web push specific fields
+func (b *NotificationBuilder) Web(fn func(*WebBuilder)) *NotificationBuilder {
+	fn(&WebBuilder{req: &b.req})
+	return b
+}
+
+// clone copies the maps and slices set by the builders
+func (r NotificationRequest) clone() NotificationRequest {
+	r.Contents = copyTexts(r.Contents)
+	r.Headings = copyTexts(r.Headings)
+	r.Subtitle = copyTexts(r.Subtitle)
+	r.IOSAttachments = copyTexts(r.IOSAttachments)
+	r.APNSAlert = copyData(r.APNSAlert)
+	r.CustomData = copyData(r.CustomData)
+	r.IncludedSegments = copyStrings(r.IncludedSegments)
+	r.ExcludedSegments = copyStrings(r.ExcludedSegments)
+	r.IncludePlayerIDs = copyStrings(r.IncludePlayerIDs)
+	r.IncludeExternalUserIDs = copyStrings(r.IncludeExternalUserIDs)
+	r.IncludeEmailTokens = copyStrings(r.IncludeEmailTokens)
+	r.IncludePhoneNumber = copyStrings(r.IncludePhoneNumber)
+	r.SMSMediaURLs = copyStrings(r.SMSMediaURLs)
+	if r.Buttons != nil {
+		r.Buttons = append([]NotificationButton{}, r.Buttons...)
+	}
+	if r.WebButtons != nil {
+		r.WebButtons = append([]NotificationButton{}, r.WebButtons...)
+	}
+	if filters, ok := r.Filters.(Filters); ok && filters != nil {
+		r.Filters = append(Filters{}, filters...)
+	}
+	return r
+}
+
+func copyTexts(texts map[string]string) map[string]string {
+	if texts == nil {
+		return nil
+	}
+	res := make(map[string]string, len(texts))
+	for k, v := range texts {
+		res[k] = v
+	}
+	return res
+}
+
+func copyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	res := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		res[k] = v
+	}
+	return res
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+func setText(texts map[string]string, language, text string) map[string]string {
+	if texts == nil {
+		texts = map[string]string{}
+	}
+	texts[language] = text
+	return texts
+}
+
+// IOSBuilder sets the iOS specific fields of a notification
+type IOSBuilder struct {
+	req *NotificationRequest
+}
+
+// Sound sets the sound file played instead of the default sound
+func (b *IOSBuilder) Sound(sound string) *IOSBuilder {
+	b.req.IOSSound = sound
+	return b
+}
+
+// Badge sets or increases the badge count of the app
+func (b *IOSBuilder) Badge(badgeType IOSBadgeType, count int) *IOSBuilder {
+	b.req.IOSBadgeType = badgeType
+	b.req.IOSBadgeCount = count
+	return b
+}
+
+// Attachment adds a media attachment, identified by id, from a local filename or an URL
+func (b *IOSBuilder) Attachment(id, url string) *IOSBuilder {
+	if b.req.IOSAttachments == nil {
+		b.req.IOSAttachments = map[string]string{}
+	}
+	b.req.IOSAttachments[id] = url
+	return b
+}
+
+// Category sets the category of the notification
+func (b *IOSBuilder) Category(category string) *IOSBuilder {
+	b.req.IOSCategory = category
+	return b
+}
+
+// Thread groups the notification with the notifications of the same thread, with a summary
+func (b *IOSBuilder) Thread(threadID, summaryArg string, summaryArgCount int) *IOSBuilder {
+	b.req.ThreadID = threadID
+	b.req.SummaryArg = summaryArg
+	b.req.SummaryArgCount = summaryArgCount
+	return b
+}
+
+// RelevanceScore sets the score used to sort grouped notifications
+func (b *IOSBuilder) RelevanceScore(score float32) *IOSBuilder {
+	b.req.IOSRelevanceScore = score
+	return b
+}
+
+// InterruptionLevel sets the interruption level of the notification
+func (b *IOSBuilder) InterruptionLevel(level IOSInterruptionLevel) *IOSBuilder {
+	b.req.IOSInterruptionLevel = level
+	return b
+}
+
+// ContentAvailable wakes the app from background
+func (b *IOSBuilder) ContentAvailable() *IOSBuilder {
+	b.req.ContentAvailable = true
+	return b
+}
+
+// Alert sets the localization parameters of the apns_alert payload
+func (b *IOSBuilder) Alert(alert map[string]interface{}) *IOSBuilder {
+	b.req.APNSAlert = alert
+	return b
+}
+
+// PushTypeOverride sets the apns-push-type header, like "voip"
+func (b *IOSBuilder) PushTypeOverride(pushType string) *IOSBuilder {
+	b.req.APNSPushTypeOverride = pushType
+	return b
+}
+
+// TargetContentIdentifier targets an App Clip experience or an app window
+func (b *IOSBuilder) TargetContentIdentifier(id string) *IOSBuilder {
+	b.req.TargetContentIdentifier = id
+	return b
+}
+
+// AndroidBuilder sets the Android specific fields of a notification
+type AndroidBuilder struct {
+	req *NotificationRequest
+}
+
+// ChannelID sets the notification category created on the dashboard
+func (b *AndroidBuilder) ChannelID(id string) *AndroidBuilder {
+	b.req.AndroidChannelID = id
+	return b
+}
+
+// ExistingChannelID sets the notification channel defined in the app
+func (b *AndroidBuilder) ExistingChannelID(id string) *AndroidBuilder {
+	b.req.ExistingAndroidChannelID = id
+	return b
+}
+
+// SmallIcon sets the icon shown in the status bar
+func (b *AndroidBuilder) SmallIcon(icon string) *AndroidBuilder {
+	b.req.SmallIcon = icon
+	return b
+}
+
+// LargeIcon sets the icon shown in the notification
+func (b *AndroidBuilder) LargeIcon(icon string) *AndroidBuilder {
+	b.req.LargeIcon = icon
+	return b
+}
+
+// BigPicture sets the picture displayed in the expanded view
+func (b *AndroidBuilder) BigPicture(picture string) *AndroidBuilder {
+	b.req.BigPicture = picture
+	return b
+}
+
+// AccentColor sets the ARGB accent color of the notification
+func (b *AndroidBuilder) AccentColor(color string) *AndroidBuilder {
+	b.req.AndroidAccentColor = color
+	return b
+}
+
+// Group stacks the notifications of the same group, with a summary message
+func (b *AndroidBuilder) Group(group string, message interface{}) *AndroidBuilder {
+	b.req.AndroidGroup = group
+	b.req.AndroidGroupMessage = message
+	return b
+}
+
+// BackgroundLayout sets the background image of the notification
+func (b *AndroidBuilder) BackgroundLayout(layout *AndroidBackgroundLayout) *AndroidBuilder {
+	b.req.AndroidBackgroundLayout = layout
+	return b
+}
+
+// BackgroundData wakes the app from background
+func (b *AndroidBuilder) BackgroundData() *AndroidBuilder {
+	b.req.AndroidBackgroundData = true
+	return b
+}
+
+// HuaweiBuilder sets the Huawei specific fields of a notification
+type HuaweiBuilder struct {
+	req *NotificationRequest
+}
+
+// MsgType sets whether the notification is a data or a message notification
+func (b *HuaweiBuilder) MsgType(msgType HuaweiMsgType) *HuaweiBuilder {
+	b.req.HuaweiMsgType = string(msgType)
+	return b
+}
+
+// ChannelID sets the notification category created on the dashboard
+func (b *HuaweiBuilder) ChannelID(id string) *HuaweiBuilder {
+	b.req.HuaweiChannelID = id
+	return b
+}
+
+// ExistingChannelID sets the notification channel defined in the app
+func (b *HuaweiBuilder) ExistingChannelID(id string) *HuaweiBuilder {
+	b.req.HuaweiExistingChannelID = id
+	return b
+}
+
+// SmallIcon sets the icon shown in the status bar
+func (b *HuaweiBuilder) SmallIcon(icon string) *HuaweiBuilder {
+	b.req.HuaweiSmallIcon = icon
+	return b
+}
+
+// LargeIcon sets the icon shown in the notification
+func (b *HuaweiBuilder) LargeIcon(icon string) *HuaweiBuilder {
+	b.req.HuaweiLargeIcon = icon
+	return b
+}
+
+// BigPicture sets the picture displayed in the expanded view
+func (b *HuaweiBuilder) BigPicture(picture string) *HuaweiBuilder {
+	b.req.HuaweiBigPicture = picture
+	return b
+}
+
+// AccentColor sets the RGB accent color of the action buttons
+func (b *HuaweiBuilder) AccentColor(color string) *HuaweiBuilder {
+	b.req.HuaweiAccentColor = color
+	return b
+}
+
+// AmazonBuilder sets the Amazon (ADM) specific fields of a notification
+type AmazonBuilder struct {
+	req *NotificationRequest
+}
+
+// SmallIcon sets the icon shown in the status bar
+func (b *AmazonBuilder) SmallIcon(icon string) *AmazonBuilder {
+	b.req.ADMSmallIcon = icon
+	return b
+}
+
+// LargeIcon sets the icon shown in the notification
+func (b *AmazonBuilder) LargeIcon(icon string) *AmazonBuilder {
+	b.req.ADMLargeIcon = icon
+	return b
+}
+
+// BigPicture sets the picture displayed in the expanded view
+func (b *AmazonBuilder) BigPicture(picture string) *AmazonBuilder {
+	b.req.ADMBigPicture = picture
+	return b
+}
+
+// Group stacks the notifications of the same group, with a summary message
+func (b *AmazonBuilder) Group(group string, message interface{}) *AmazonBuilder {
+	b.req.ADMGroup = group
+	b.req.ADMGroupMessage = message
+	return b
+}
+
+// BackgroundData wakes the app from background
+func (b *AmazonBuilder) BackgroundData() *AmazonBuilder {
+	b.req.AmazonBackgroundData = true
+	return b
+}
+
+// WebBuilder sets the web push specific fields of a notification
+type WebBuilder struct {
+	req *NotificationRequest
+}
+
+// Icon sets the icon of the notification
+func (b *WebBuilder) Icon(url string) *WebBuilder {
+	b.req.ChromeWebIcon = url
+	return b
+}
+
+// FirefoxIcon sets the icon of the notification on Firefox
+func (b *WebBuilder) FirefoxIcon(url string) *WebBuilder {
+	b.req.FirefoxIcon = url
+	return b
+}
+
+// Image sets the large image shown below the text
+func (b *WebBuilder) Image(url string) *WebBuilder {
+	b.req.ChromeWebImage = url
+	return b
+}
+
+// Badge sets the icon shown in the Android notification shade
+func (b *WebBuilder) Badge(url string) *WebBuilder {
+	b.req.ChromeWebBadge = url
+	return b
+}
+
+// URL sets the URL opened on web push platforms
+func (b *WebBuilder) URL(url string) *WebBuilder {
+	b.req.WebURL = url
+	return b
+}
+
+// Topic displays multiple notifications at once with different topics
+func (b *WebBuilder) Topic(topic string) *WebBuilder {
+	b.req.WebPushTopic = topic
+	return b
+}
+
+// Button adds an action button to the web push notification
+func (b *WebBuilder) Button(button NotificationButton) *WebBuilder {
+	b.req.WebButtons = append(b.req.WebButtons, button)
+	return b
+}
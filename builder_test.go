@@ -0,0 +1,154 @@
+package onesignal
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotificationBuilder_push(t *testing.T) {
+	sendAfter := time.Date(2021, 9, 24, 14, 0, 0, 0, time.FixedZone("", -7*3600))
+
+	got, err := NewPush().
+		Heading("en", "Hello").
+		Content("en", "World").
+		Content("fr", "Monde").
+		ToSegments("Subscribed Users").
+		ExcludeSegments("Inactive Users").
+		SendAfter(sendAfter).
+		TTL(time.Hour).
+		IOS(func(b *IOSBuilder) {
+			b.Sound("ping.aiff").Badge(IOSBadgeTypeIncrease, 1).Attachment("image", "https://example.com/image.png")
+		}).
+		Android(func(b *AndroidBuilder) {
+			b.ChannelID("news").BigPicture("https://example.com/image.png")
+		}).
+		Huawei(func(b *HuaweiBuilder) {
+			b.MsgType(HuaweiMsgTypeMessage)
+		}).
+		Web(func(b *WebBuilder) {
+			b.Icon("https://example.com/icon.png").Button(NotificationButton{ID: "read", Text: "Read"})
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned an error: %v", err)
+	}
+
+	want := &NotificationRequest{
+		Headings:         map[string]string{"en": "Hello"},
+		Contents:         map[string]string{"en": "World", "fr": "Monde"},
+		IncludedSegments: []string{"Subscribed Users"},
+		ExcludedSegments: []string{"Inactive Users"},
+		SendAfter:        "2021-09-24 14:00:00 GMT-0700",
+		TTL:              3600,
+		IOSSound:         "ping.aiff",
+		IOSBadgeType:     IOSBadgeTypeIncrease,
+		IOSBadgeCount:    1,
+		IOSAttachments:   map[string]string{"image": "https://example.com/image.png"},
+		AndroidChannelID: "news",
+		BigPicture:       "https://example.com/image.png",
+		HuaweiMsgType:    "message",
+		ChromeWebIcon:    "https://example.com/icon.png",
+		WebButtons:       []NotificationButton{{ID: "read", Text: "Read"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build returned %+v, want %+v", got, want)
+	}
+}
+
+func TestNotificationBuilder_channelForExternalUserIDs(t *testing.T) {
+	for name, tc := range map[string]struct {
+		builder *NotificationBuilder
+		want    MessageType
+	}{
+		"push":  {NewPush().Content("en", "Hello"), MessageTypePush},
+		"email": {NewEmail("Subject", `<a href="[unsubscribe_url]">Unsubscribe</a>`), MessageTypeEmail},
+		"sms":   {NewSMS("Hello"), MessageTypeSMS},
+	} {
+		req, err := tc.builder.ToExternalUsers("user-id").Build()
+		if err != nil {
+			t.Errorf("%s: Build returned an error: %v", name, err)
+			continue
+		}
+		if req.ChannelForExternalUserIDs != tc.want {
+			t.Errorf("%s: ChannelForExternalUserIDs is %q, want %q", name, req.ChannelForExternalUserIDs, tc.want)
+		}
+	}
+
+	req, _ := NewEmail("Subject", "[unsubscribe_url]").ToEmails("foo@example.com").Build()
+	if req.ChannelForExternalUserIDs != "" {
+		t.Errorf("ChannelForExternalUserIDs should only be set when targeting external user ids")
+	}
+}
+
+func TestNotificationBuilder_reuse(t *testing.T) {
+	b := NewPush().
+		Content("en", "Hello").
+		ToSegments("Subscribed Users").
+		IOS(func(b *IOSBuilder) { b.Attachment("image", "https://example.com/image.png") })
+
+	first, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build returned an error: %v", err)
+	}
+	first.IncludedSegments[0] = "Inactive Users"
+
+	second, err := b.Content("en", "Changed").
+		IOS(func(b *IOSBuilder) { b.Attachment("image", "https://example.com/other.png") }).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned an error: %v", err)
+	}
+
+	if first.Contents["en"] != "Hello" || first.IOSAttachments["image"] != "https://example.com/image.png" {
+		t.Errorf("the first request was changed by the builder: %+v", first)
+	}
+	if second.Contents["en"] != "Changed" || !reflect.DeepEqual(second.IncludedSegments, []string{"Subscribed Users"}) {
+		t.Errorf("the second request was changed by the first one: %+v", second)
+	}
+}
+
+func TestNotificationBuilder_invalid(t *testing.T) {
+	req, err := NewEmail("Subject", "Body").ToSegments("Subscribed Users").Build()
+	if got, want := validationFields(t, err), []string{"email_body"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Build validation errors on %v, want %v", got, want)
+	}
+	if req == nil || req.EmailSubject != "Subject" {
+		t.Errorf("Build should return the request along with the validation errors: %+v", req)
+	}
+}
+
+func TestNotificationBuilder_create(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	req, err := NewSMS("Hello").
+		ToFilters(FilterCountry("US"), FilterOr(), FilterCountry("CA")).
+		SMSFrom("+15555550100").
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned an error: %v", err)
+	}
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, &NotificationRequest{}, &NotificationRequest{
+			AppID:    client.appID,
+			Contents: map[string]string{"en": "Hello"},
+			Filters: []interface{}{
+				map[string]interface{}{"field": "country", "relation": "=", "value": "US"},
+				map[string]interface{}{"operator": "OR"},
+				map[string]interface{}{"field": "country", "relation": "=", "value": "CA"},
+			},
+			SMSFrom: "+15555550100",
+		})
+		fmt.Fprint(w, `{"id":"notif-fake-id","recipients":1}`)
+	})
+
+	client.SetRequestValidation(true)
+	if _, _, err := client.Notifications.Create(req); err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+}
@@ -0,0 +1,432 @@
+package onesignal
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCSVPollInterval    = 5 * time.Second
+	defaultCSVMaxPollInterval = time.Minute
+)
+
+// csvTimeLayouts lists the formats of the dates of the CSV exports
+var csvTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05.999999-07",
+	time.RFC3339,
+}
+
+// CSVDownloadOptions specifies how to wait for a CSV export to become available
+type CSVDownloadOptions struct {
+	// Delay before checking the file again. Defaults to 5 seconds, doubled at each attempt.
+	PollInterval time.Duration
+	// Maximum delay between two checks. Defaults to 1 minute.
+	MaxPollInterval time.Duration
+}
+
+// downloadCSV waits until the CSV file at fileURL is available and returns its content, gunzipped if needed.
+// The file is generated asynchronously and is reported missing until it is ready.
+// Use the context to limit the time spent waiting.
+func (c *httpClient) downloadCSV(ctx context.Context, fileURL string, opt CSVDownloadOptions) (io.ReadCloser, error) {
+	interval := opt.PollInterval
+	if interval <= 0 {
+		interval = defaultCSVPollInterval
+	}
+	maxInterval := opt.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultCSVMaxPollInterval
+	}
+
+	for {
+		// the file URL is pre-signed, the API key must not be sent
+		req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return newCSVBody(resp.Body)
+		case http.StatusForbidden, http.StatusNotFound:
+			resp.Body.Close()
+		default:
+			body, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, newAPIError(resp, body)
+		}
+
+		c.log(ctx, LogLevelDebug, "CSV file not ready", "status", resp.StatusCode, "wait", interval)
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// csvBody reads a CSV file, gunzipping it if needed
+type csvBody struct {
+	io.Reader
+	body io.Closer
+}
+
+func newCSVBody(body io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(body)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		body.Close()
+		return nil, err
+	}
+
+	// the file may have been decompressed by a transport honoring Content-Encoding
+	if len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return &csvBody{Reader: br, body: body}, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	return &csvBody{Reader: zr, body: body}, nil
+}
+
+func (b *csvBody) Close() error {
+	return b.body.Close()
+}
+
+// csvRows reads the rows of a CSV file with a header
+type csvRows struct {
+	reader  *csv.Reader
+	columns map[string]int
+	record  []string
+	line    int
+}
+
+func newCSVRows(r io.Reader) (*csvRows, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &csvRows{reader: reader}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	return &csvRows{reader: reader, columns: columns, line: 1}, nil
+}
+
+// next reads the next row. It returns io.EOF at the end of the file.
+func (r *csvRows) next() error {
+	if r.columns == nil {
+		return io.EOF
+	}
+	record, err := r.reader.Read()
+	if err != nil {
+		return err
+	}
+	r.record = record
+	r.line++
+	return nil
+}
+
+// get returns the value of a column of the current row, or an empty string if the column doesn't exist
+func (r *csvRows) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return r.record[i]
+}
+
+// column returns the first of the column names present in the header
+func (r *csvRows) column(names ...string) string {
+	for _, name := range names {
+		if _, ok := r.columns[name]; ok {
+			return name
+		}
+	}
+	return names[0]
+}
+
+// csvParser decodes the columns of a row, recording the first error
+type csvParser struct {
+	rows *csvRows
+	err  error
+}
+
+func (p *csvParser) fail(column, value string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("csv line %d: invalid %s %q: %v", p.rows.line, column, value, err)
+	}
+}
+
+func (p *csvParser) string(column string) string {
+	return p.rows.get(column)
+}
+
+func (p *csvParser) int(column string) int {
+	v := p.rows.get(column)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		p.fail(column, v, err)
+	}
+	return n
+}
+
+func (p *csvParser) float(column string) float64 {
+	v := p.rows.get(column)
+	if v == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		p.fail(column, v, err)
+	}
+	return f
+}
+
+func (p *csvParser) bool(column string) bool {
+	v := p.rows.get(column)
+	if v == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		p.fail(column, v, err)
+	}
+	return b
+}
+
+// unix returns the unix timestamp of a date column
+func (p *csvParser) unix(column string) int {
+	v := p.rows.get(column)
+	if v == "" {
+		return 0
+	}
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return int(n)
+	}
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return int(t.Unix())
+		}
+	}
+	p.fail(column, v, fmt.Errorf("unknown date format"))
+	return 0
+}
+
+// location returns the latitude and the longitude of a "(lat, long)" column
+func (p *csvParser) location(column string) (float64, float64) {
+	v := p.rows.get(column)
+	if v == "" {
+		return 0, 0
+	}
+	parts := strings.Split(strings.Trim(v, "() "), ",")
+	if len(parts) != 2 {
+		p.fail(column, v, fmt.Errorf("expected a latitude and a longitude"))
+		return 0, 0
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		p.fail(column, v, err)
+	}
+	long, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		p.fail(column, v, err)
+	}
+	return lat, long
+}
+
+func (p *csvParser) tags(column string) map[string]string {
+	v := p.rows.get(column)
+	if v == "" {
+		return nil
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(v), &raw); err != nil {
+		p.fail(column, v, err)
+		return nil
+	}
+	tags := make(map[string]string, len(raw))
+	for k, t := range raw {
+		if s, ok := t.(string); ok {
+			tags[k] = s
+		} else {
+			tags[k] = fmt.Sprint(t)
+		}
+	}
+	return tags
+}
+
+// PlayerCSVIterator streams the players of a CSV export, one row at a time.
+//
+//	it, err := client.Players.DownloadCSVExport(ctx, &onesignal.PlayerCSVExportOptions{
+//		ExtraFields: []string{"location", "country"},
+//	})
+//	if err != nil {
+//		...
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		player := it.Player()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type PlayerCSVIterator struct {
+	csv     *csvIterator
+	current Player
+}
+
+// csvIterator reads the rows of a downloaded CSV file
+type csvIterator struct {
+	body io.ReadCloser
+	rows *csvRows
+	err  error
+}
+
+// openCSV waits until the CSV file at fileURL is available and reads its header
+func (c *httpClient) openCSV(ctx context.Context, fileURL string, dlOpt []CSVDownloadOptions) (*csvIterator, error) {
+	var opt CSVDownloadOptions
+	if len(dlOpt) > 0 {
+		opt = dlOpt[0]
+	}
+
+	body, err := c.downloadCSV(ctx, fileURL, opt)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := newCSVRows(body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	return &csvIterator{body: body, rows: rows}, nil
+}
+
+// next reads the next row and decodes it with decode.
+// It returns false at the end of the file or when an error occurred.
+func (it *csvIterator) next(decode func(p *csvParser)) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if err := it.rows.next(); err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+		return false
+	}
+
+	p := &csvParser{rows: it.rows}
+	decode(p)
+	if p.err != nil {
+		it.err = p.err
+		return false
+	}
+
+	return true
+}
+
+// DownloadCSVExport requests a CSV export of the players, waits until the file is available,
+// and returns an iterator over its rows.
+func (s *PlayersService) DownloadCSVExport(ctx context.Context, opt *PlayerCSVExportOptions, dlOpt ...CSVDownloadOptions) (*PlayerCSVIterator, error) {
+	var op []PlayerCSVExportOptions
+	if opt != nil {
+		op = append(op, *opt)
+	}
+	res, _, err := s.CSVExportContext(ctx, op...)
+	if err != nil {
+		return nil, err
+	}
+	return s.OpenCSVExport(ctx, res.CSVFileURL, dlOpt...)
+}
+
+// OpenCSVExport waits until the CSV export at fileURL is available and returns an iterator over its rows.
+func (s *PlayersService) OpenCSVExport(ctx context.Context, fileURL string, dlOpt ...CSVDownloadOptions) (*PlayerCSVIterator, error) {
+	it, err := s.client.openCSV(ctx, fileURL, dlOpt)
+	if err != nil {
+		return nil, err
+	}
+	return &PlayerCSVIterator{csv: it}, nil
+}
+
+// Next reads the next player. It returns false at the end of the file or when an error occurred.
+func (it *PlayerCSVIterator) Next() bool {
+	return it.csv.next(func(p *csvParser) {
+		it.current = Player{
+			ID:                p.string("id"),
+			Identifier:        p.string("identifier"),
+			SessionCount:      p.int("session_count"),
+			Language:          p.string("language"),
+			Timezone:          p.int("timezone"),
+			GameVersion:       p.string("game_version"),
+			DeviceOS:          p.string("device_os"),
+			DeviceType:        DeviceType(p.int("device_type")),
+			DeviceModel:       p.string("device_model"),
+			AdID:              p.string("ad_id"),
+			Tags:              p.tags("tags"),
+			LastActive:        p.unix("last_active"),
+			Playtime:          p.int("playtime"),
+			AmountSpent:       float32(p.float("amount_spent")),
+			CreatedAt:         p.unix("created_at"),
+			InvalidIdentifier: p.bool("invalid_identifier"),
+			BadgeCount:        p.int("badge_count"),
+			SDK:               p.string("sdk"),
+			TestType:          p.int("test_type"),
+			IP:                p.string("ip"),
+			ExternalUserID:    p.string("external_user_id"),
+			NotificationTypes: p.int("notification_types"),
+			Country:           p.string("country"),
+			Rooted:            p.bool("rooted"),
+			WebAuth:           p.string("web_auth"),
+			WebP256:           p.string("web_p256"),
+		}
+		it.current.Lat, it.current.Long = p.location("location")
+	})
+}
+
+// Player returns the current player
+func (it *PlayerCSVIterator) Player() Player {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *PlayerCSVIterator) Err() error {
+	return it.csv.err
+}
+
+// Close closes the downloaded file
+func (it *PlayerCSVIterator) Close() error {
+	return it.csv.body.Close()
+}
@@ -0,0 +1,174 @@
+package onesignal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const samplePlayersCSV = `id,identifier,session_count,language,timezone,game_version,device_os,device_type,device_model,ad_id,tags,last_active,playtime,amount_spent,created_at,invalid_identifier,badge_count,location,country,rooted,ip,web_auth,web_p256
+id123,ce777617da7f548f,1,en,-28800,1.0,7.0.4,0,"iPhone7,2",,"{""a"":""1"",""level"":10}",2021-01-19 23:15:43,12,1.99,1610000000,f,0,"(37.7749, -122.4194)",US,t,127.0.0.1,auth,p256
+id456,,3,fr,3600,1.1,10,1,Pixel,,,,,,,,,,,,,,
+`
+
+func gzipString(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestPlayersService_DownloadCSVExport(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	attempts := 0
+	fileServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("The API key should not be sent to the file server")
+		}
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write(gzipString(t, samplePlayersCSV))
+	}))
+	defer fileServer.Close()
+
+	mux.HandleFunc("/players/csv_export", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, &PlayerCSVExportOptions{}, &PlayerCSVExportOptions{
+			ExtraFields: []string{"location", "country", "rooted", "ip", "web_auth", "web_p256"},
+		})
+		fmt.Fprintf(w, `{"csv_file_url": "%s/players.csv.gz"}`, fileServer.URL)
+	})
+
+	it, err := client.Players.DownloadCSVExport(context.Background(), &PlayerCSVExportOptions{
+		ExtraFields: []string{"location", "country", "rooted", "ip", "web_auth", "web_p256"},
+	}, CSVDownloadOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("DownloadCSVExport returned an error: %v", err)
+	}
+	defer it.Close()
+
+	var players []Player
+	for it.Next() {
+		players = append(players, it.Player())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iteration returned an error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("File requested %d times, want %d", attempts, 3)
+	}
+
+	want := []Player{
+		{
+			ID:           "id123",
+			Identifier:   "ce777617da7f548f",
+			SessionCount: 1,
+			Language:     "en",
+			Timezone:     -28800,
+			GameVersion:  "1.0",
+			DeviceOS:     "7.0.4",
+			DeviceType:   0,
+			DeviceModel:  "iPhone7,2",
+			Tags:         map[string]string{"a": "1", "level": "10"},
+			LastActive:   int(time.Date(2021, 1, 19, 23, 15, 43, 0, time.UTC).Unix()),
+			Playtime:     12,
+			AmountSpent:  1.99,
+			CreatedAt:    1610000000,
+			Lat:          37.7749,
+			Long:         -122.4194,
+			Country:      "US",
+			Rooted:       true,
+			IP:           "127.0.0.1",
+			WebAuth:      "auth",
+			WebP256:      "p256",
+		},
+		{
+			ID:           "id456",
+			SessionCount: 3,
+			Language:     "fr",
+			Timezone:     3600,
+			GameVersion:  "1.1",
+			DeviceOS:     "10",
+			DeviceType:   1,
+			DeviceModel:  "Pixel",
+		},
+	}
+	if !reflect.DeepEqual(players, want) {
+		t.Errorf("Players: %+v, want %+v", players, want)
+	}
+}
+
+func TestPlayersService_OpenCSVExport_notGzipped(t *testing.T) {
+	fileServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "id,session_count\nid123,2\n")
+	}))
+	defer fileServer.Close()
+
+	client := setupClient(t)
+	it, err := client.Players.OpenCSVExport(context.Background(), fileServer.URL)
+	if err != nil {
+		t.Fatalf("OpenCSVExport returned an error: %v", err)
+	}
+	defer it.Close()
+
+	if !it.Next() || it.Player().ID != "id123" || it.Player().SessionCount != 2 {
+		t.Errorf("Unexpected player: %+v, error: %v", it.Player(), it.Err())
+	}
+	if it.Next() {
+		t.Errorf("Iteration should be done")
+	}
+}
+
+func TestPlayersService_OpenCSVExport_invalidRow(t *testing.T) {
+	fileServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "id,session_count\nid123,many\n")
+	}))
+	defer fileServer.Close()
+
+	client := setupClient(t)
+	it, err := client.Players.OpenCSVExport(context.Background(), fileServer.URL)
+	if err != nil {
+		t.Fatalf("OpenCSVExport returned an error: %v", err)
+	}
+	defer it.Close()
+
+	if it.Next() {
+		t.Errorf("Next should fail on an invalid row")
+	}
+	if err := it.Err(); err == nil || !strings.Contains(err.Error(), "csv line 2: invalid session_count") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPlayersService_OpenCSVExport_contextDone(t *testing.T) {
+	fileServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer fileServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	client := setupClient(t)
+	_, err := client.Players.OpenCSVExport(ctx, fileServer.URL, CSVDownloadOptions{PollInterval: 5 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("OpenCSVExport should return the context error, got %v", err)
+	}
+}
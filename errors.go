@@ -0,0 +1,164 @@
+package onesignal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit represents the rate limit information sent in the headers of an API response.
+type RateLimit struct {
+	// The maximum number of requests allowed in the current window. Zero if unknown.
+	Limit int
+	// The number of requests left in the current window. -1 if unknown.
+	Remaining int
+	// The time at which the current window resets. Zero if unknown.
+	Reset time.Time
+	// The delay requested by the Retry-After header. Zero if unknown.
+	RetryAfter time.Duration
+}
+
+// parseRateLimit reads the X-RateLimit-* and Retry-After headers
+func parseRateLimit(h http.Header) RateLimit {
+	rl := RateLimit{Remaining: -1}
+	if v, err := strconv.Atoi(h.Get("X-RateLimit-Limit")); err == nil {
+		rl.Limit = v
+	}
+	if v, err := strconv.Atoi(h.Get("X-RateLimit-Remaining")); err == nil {
+		rl.Remaining = v
+	}
+	if v, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(v, 0)
+	}
+	if d, ok := parseRetryAfter(h.Get("Retry-After"), time.Now()); ok {
+		rl.RetryAfter = d
+	}
+
+	return rl
+}
+
+// APIError reports a non-successful HTTP response of the OneSignal API.
+// The parsed ErrorResponse, if any, can be retrieved with errors.As.
+type APIError struct {
+	// HTTP status code of the response
+	StatusCode int
+	// HTTP method of the request
+	Method string
+	// URL of the request
+	URL string
+	// Headers of the response
+	Header http.Header
+	// Rate limit information of the response
+	RateLimit RateLimit
+	// Raw body of the response
+	Body []byte
+	// Error messages parsed from the response body
+	Messages []string
+	// Parsed response body. Nil if the body isn't a valid JSON error response.
+	Response *ErrorResponse
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("OneSignal API error: %s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if len(e.Messages) > 0 {
+		msg += "\n - " + strings.Join(e.Messages, "\n - ")
+	}
+	return msg
+}
+
+// Unwrap returns the parsed ErrorResponse, if any
+func (e *APIError) Unwrap() error {
+	if e.Response == nil {
+		return nil
+	}
+	return e.Response
+}
+
+// newAPIError builds an APIError from the response and its already read body
+func newAPIError(r *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: r.StatusCode,
+		Header:     r.Header,
+		RateLimit:  parseRateLimit(r.Header),
+		Body:       body,
+	}
+	if r.Request != nil {
+		apiErr.Method = r.Request.Method
+		apiErr.URL = redactURL(r.Request.URL)
+	}
+
+	errResp := new(ErrorResponse)
+	if err := json.Unmarshal(body, errResp); err == nil {
+		apiErr.Response = errResp
+		apiErr.Messages = errResp.Messages
+	}
+
+	return apiErr
+}
+
+// redactURL returns the URL with the values of the secret query parameters, like email_auth_hash, redacted
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		key := strings.SplitN(param, "=", 2)[0]
+		if name, err := url.QueryUnescape(key); err == nil && isSecretField(name) {
+			params[i] = key + "=" + redactedValue
+		}
+	}
+	redacted := *u
+	redacted.RawQuery = strings.Join(params, "&")
+	return redacted.String()
+}
+
+// AsAPIError returns the APIError in the chain of err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsStatus reports whether err is an APIError with the given HTTP status code
+func IsStatus(err error, statusCode int) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is an API error caused by a missing resource
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err is an API error caused by exceeding the rate limit,
+// or ErrRateLimited returned by the client side rate limiter
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited) || IsStatus(err, http.StatusTooManyRequests)
+}
+
+// IsInvalidPlayerIDs reports whether err is an API error listing invalid player ids
+func IsInvalidPlayerIDs(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.Response == nil {
+		return false
+	}
+
+	if len(apiErr.Response.Details.InvalidPlayerIDs) > 0 {
+		return true
+	}
+	for _, m := range apiErr.Response.Details.Messages {
+		if strings.Contains(strings.ToLower(m), "invalid player ids") {
+			return true
+		}
+	}
+
+	return false
+}
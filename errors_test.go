@@ -0,0 +1,102 @@
+package onesignal
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIError_helpers(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/players/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors":["No user with this id found"]}`)
+	})
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1622541600")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"errors":["API rate limit exceeded"]}`)
+	})
+
+	_, _, err := client.Players.Get("missing")
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) should be true", err)
+	}
+	if IsRateLimited(err) {
+		t.Errorf("IsRateLimited(%v) should be false", err)
+	}
+
+	_, _, err = client.Notifications.Create(sampleNotificationRequest)
+	if !IsRateLimited(err) {
+		t.Errorf("IsRateLimited(%v) should be true", err)
+	}
+
+	apiErr, _ := AsAPIError(err)
+	want := RateLimit{
+		Limit:      100,
+		Remaining:  0,
+		Reset:      time.Unix(1622541600, 0),
+		RetryAfter: 30 * time.Second,
+	}
+	if apiErr.RateLimit != want {
+		t.Errorf("RateLimit: %+v, want %+v", apiErr.RateLimit, want)
+	}
+
+	if got, want := apiErr.Messages, []string{"API rate limit exceeded"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Messages: %v, want %v", got, want)
+	}
+
+	if got, want := apiErr.Method, "POST"; got != want {
+		t.Errorf("Method: %v, want %v", got, want)
+	}
+}
+
+func TestIsInvalidPlayerIDs(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want bool
+	}{
+		{`{"errors":{"invalid_player_ids":["5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986"]}}`, true},
+		{`{"errors":["Invalid player ids 5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986"]}`, true},
+		{`{"errors":["Notification content must not be null for any languages."]}`, false},
+		{`Bad Request`, false},
+	} {
+		err := newAPIError(&http.Response{StatusCode: http.StatusBadRequest}, []byte(tc.body))
+		if got := IsInvalidPlayerIDs(err); got != tc.want {
+			t.Errorf("IsInvalidPlayerIDs(%s) = %v, want %v", tc.body, got, tc.want)
+		}
+	}
+
+	if IsInvalidPlayerIDs(fmt.Errorf("not an api error")) {
+		t.Errorf("IsInvalidPlayerIDs should be false for other errors")
+	}
+}
+
+func TestAPIError_redactedURL(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/players/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors":["No user with this id found"]}`)
+	})
+
+	_, _, err := client.Players.Get("missing", PlayerGetOptions{EmailAuthHash: "secret-hash"})
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("Get returned %v, want an API error", err)
+	}
+	if strings.Contains(apiErr.URL, "secret-hash") || strings.Contains(err.Error(), "secret-hash") {
+		t.Errorf("the error %q contains the auth hash", err)
+	}
+	if !strings.Contains(apiErr.URL, "email_auth_hash=[REDACTED]") || !strings.Contains(apiErr.URL, "app_id=fake-app-id") {
+		t.Errorf("URL: %v, want the auth hash redacted and the other parameters kept", apiErr.URL)
+	}
+}
@@ -0,0 +1,271 @@
+package onesignal
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FilterField is the user property compared by a filter.
+// https://documentation.onesignal.com/reference/create-notification#send-to-users-based-on-filters
+type FilterField string
+
+// FilterRelation is the comparison operator of a filter.
+type FilterRelation string
+
+// FilterOperator combines filters. Filters are combined with AND unless separated by an OR operator.
+type FilterOperator string
+
+const (
+	// Filter by a tag of the user. Requires a key.
+	FilterFieldTag FilterField = "tag"
+	// Hours since the last session of the user
+	FilterFieldLastSession FilterField = "last_session"
+	// Hours since the first session of the user
+	FilterFieldFirstSession FilterField = "first_session"
+	// Number of sessions of the user
+	FilterFieldSessionCount FilterField = "session_count"
+	// Seconds spent by the user in the app
+	FilterFieldSessionTime FilterField = "session_time"
+	// Amount in USD spent by the user
+	FilterFieldAmountSpent FilterField = "amount_spent"
+	// Amount in USD spent by the user on a SKU. Requires a key.
+	FilterFieldBoughtSKU FilterField = "bought_sku"
+	// 2 character language code of the user
+	FilterFieldLanguage FilterField = "language"
+	// Version of the app used by the user
+	FilterFieldAppVersion FilterField = "app_version"
+	// Users within a radius in meters of a position
+	FilterFieldLocation FilterField = "location"
+	// Email address of the user
+	FilterFieldEmail FilterField = "email"
+	// 2 character ISO 3166-1 country code of the user
+	FilterFieldCountry FilterField = "country"
+
+	FilterRelationGreaterThan FilterRelation = ">"
+	FilterRelationLessThan    FilterRelation = "<"
+	FilterRelationEqual       FilterRelation = "="
+	FilterRelationNotEqual    FilterRelation = "!="
+	FilterRelationExists      FilterRelation = "exists"
+	FilterRelationNotExists   FilterRelation = "not_exists"
+	// The tag value is a unix timestamp older than value seconds
+	FilterRelationTimeElapsedGreaterThan FilterRelation = "time_elapsed_gt"
+	// The tag value is a unix timestamp newer than value seconds
+	FilterRelationTimeElapsedLessThan FilterRelation = "time_elapsed_lt"
+
+	FilterOperatorOR  FilterOperator = "OR"
+	FilterOperatorAND FilterOperator = "AND"
+)
+
+// maxFilters is the maximum number of entries in the filters of a request
+const maxFilters = 200
+
+// filterRelations lists the relations supported by each field.
+// A nil list means that the field doesn't take a relation.
+var filterRelations = map[FilterField][]FilterRelation{
+	FilterFieldTag: {
+		FilterRelationGreaterThan, FilterRelationLessThan, FilterRelationEqual, FilterRelationNotEqual,
+		FilterRelationExists, FilterRelationNotExists,
+		FilterRelationTimeElapsedGreaterThan, FilterRelationTimeElapsedLessThan,
+	},
+	FilterFieldLastSession:  {FilterRelationGreaterThan, FilterRelationLessThan},
+	FilterFieldFirstSession: {FilterRelationGreaterThan, FilterRelationLessThan},
+	FilterFieldSessionCount: {FilterRelationGreaterThan, FilterRelationLessThan, FilterRelationEqual, FilterRelationNotEqual},
+	FilterFieldSessionTime:  {FilterRelationGreaterThan, FilterRelationLessThan},
+	FilterFieldAmountSpent:  {FilterRelationGreaterThan, FilterRelationLessThan, FilterRelationEqual},
+	FilterFieldBoughtSKU:    {FilterRelationGreaterThan, FilterRelationLessThan, FilterRelationEqual},
+	FilterFieldLanguage:     {FilterRelationEqual, FilterRelationNotEqual},
+	FilterFieldAppVersion:   {FilterRelationGreaterThan, FilterRelationLessThan, FilterRelationEqual, FilterRelationNotEqual},
+	FilterFieldLocation:     nil,
+	FilterFieldEmail:        nil,
+	FilterFieldCountry:      {FilterRelationEqual},
+}
+
+// Filter is an entry of the filters of a notification or a segment,
+// either a condition on a user property or an operator.
+// Use the Filter* constructors to build valid conditions.
+type Filter struct {
+	Field    FilterField    `json:"field,omitempty"`
+	Key      string         `json:"key,omitempty"`
+	Relation FilterRelation `json:"relation,omitempty"`
+	Value    string         `json:"value,omitempty"`
+	HoursAgo string         `json:"hours_ago,omitempty"`
+	Radius   string         `json:"radius,omitempty"`
+	Lat      string         `json:"lat,omitempty"`
+	Long     string         `json:"long,omitempty"`
+	Operator FilterOperator `json:"operator,omitempty"`
+}
+
+// Filters is a list of filters, combined with AND unless separated by an OR operator.
+// It can be assigned to NotificationRequest.Filters.
+//
+//	onesignal.Filters{
+//		onesignal.FilterTag("level", onesignal.FilterRelationGreaterThan, "10"),
+//		onesignal.FilterAmountSpent(onesignal.FilterRelationGreaterThan, 0),
+//		onesignal.FilterOr(),
+//		onesignal.FilterCountry("US"),
+//	}
+type Filters []Filter
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// FilterTag filters users by a tag. The value is ignored by the exists and not_exists relations,
+// and is a number of seconds for the time_elapsed_gt and time_elapsed_lt relations.
+func FilterTag(key string, relation FilterRelation, value string) Filter {
+	return Filter{Field: FilterFieldTag, Key: key, Relation: relation, Value: value}
+}
+
+// FilterLastSession filters users by the hours since their last session
+func FilterLastSession(relation FilterRelation, hoursAgo float64) Filter {
+	return Filter{Field: FilterFieldLastSession, Relation: relation, HoursAgo: formatFloat(hoursAgo)}
+}
+
+// FilterFirstSession filters users by the hours since their first session
+func FilterFirstSession(relation FilterRelation, hoursAgo float64) Filter {
+	return Filter{Field: FilterFieldFirstSession, Relation: relation, HoursAgo: formatFloat(hoursAgo)}
+}
+
+// FilterSessionCount filters users by their number of sessions
+func FilterSessionCount(relation FilterRelation, count int) Filter {
+	return Filter{Field: FilterFieldSessionCount, Relation: relation, Value: strconv.Itoa(count)}
+}
+
+// FilterSessionTime filters users by the seconds they spent in the app
+func FilterSessionTime(relation FilterRelation, seconds int) Filter {
+	return Filter{Field: FilterFieldSessionTime, Relation: relation, Value: strconv.Itoa(seconds)}
+}
+
+// FilterAmountSpent filters users by the amount in USD they spent
+func FilterAmountSpent(relation FilterRelation, amount float64) Filter {
+	return Filter{Field: FilterFieldAmountSpent, Relation: relation, Value: formatFloat(amount)}
+}
+
+// FilterBoughtSKU filters users by the amount in USD they spent on a SKU
+func FilterBoughtSKU(sku string, relation FilterRelation, amount float64) Filter {
+	return Filter{Field: FilterFieldBoughtSKU, Key: sku, Relation: relation, Value: formatFloat(amount)}
+}
+
+// FilterLanguage filters users by their 2 character language code
+func FilterLanguage(relation FilterRelation, language string) Filter {
+	return Filter{Field: FilterFieldLanguage, Relation: relation, Value: language}
+}
+
+// FilterAppVersion filters users by the version of the app
+func FilterAppVersion(relation FilterRelation, version string) Filter {
+	return Filter{Field: FilterFieldAppVersion, Relation: relation, Value: version}
+}
+
+// FilterLocation filters users within a radius in meters of a position
+func FilterLocation(radius, lat, long float64) Filter {
+	return Filter{Field: FilterFieldLocation, Radius: formatFloat(radius), Lat: formatFloat(lat), Long: formatFloat(long)}
+}
+
+// FilterEmail filters users by their email address
+func FilterEmail(email string) Filter {
+	return Filter{Field: FilterFieldEmail, Value: email}
+}
+
+// FilterCountry filters users by their 2 character ISO 3166-1 country code
+func FilterCountry(country string) Filter {
+	return Filter{Field: FilterFieldCountry, Relation: FilterRelationEqual, Value: country}
+}
+
+// FilterOr separates groups of filters combined with AND
+func FilterOr() Filter {
+	return Filter{Operator: FilterOperatorOR}
+}
+
+// isOperator reports whether the filter is an operator entry
+func (f Filter) isOperator() bool {
+	return f.Operator != ""
+}
+
+// Validate checks that the filter compares a known field with one of its supported relations
+func (f Filter) Validate() error {
+	if f.isOperator() {
+		if f.Operator != FilterOperatorOR && f.Operator != FilterOperatorAND {
+			return fmt.Errorf("unknown filter operator %q", f.Operator)
+		}
+		if f.Field != "" {
+			return fmt.Errorf("filter operator %q can't have a field", f.Operator)
+		}
+		return nil
+	}
+
+	relations, ok := filterRelations[f.Field]
+	if !ok {
+		return fmt.Errorf("unknown filter field %q", f.Field)
+	}
+
+	if relations == nil {
+		if f.Relation != "" {
+			return fmt.Errorf("filter field %q doesn't support relations", f.Field)
+		}
+	} else if !containsRelation(relations, f.Relation) {
+		return fmt.Errorf("filter field %q doesn't support relation %q", f.Field, f.Relation)
+	}
+
+	switch f.Field {
+	case FilterFieldTag, FilterFieldBoughtSKU:
+		if f.Key == "" {
+			return fmt.Errorf("filter field %q requires a key", f.Field)
+		}
+	case FilterFieldLastSession, FilterFieldFirstSession:
+		if _, err := strconv.ParseFloat(f.HoursAgo, 64); err != nil {
+			return fmt.Errorf("filter field %q requires a numeric hours_ago, got %q", f.Field, f.HoursAgo)
+		}
+	case FilterFieldLocation:
+		for name, v := range map[string]string{"radius": f.Radius, "lat": f.Lat, "long": f.Long} {
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("filter field %q requires a numeric %s, got %q", f.Field, name, v)
+			}
+		}
+	}
+
+	switch f.Field {
+	case FilterFieldSessionCount, FilterFieldSessionTime, FilterFieldAmountSpent, FilterFieldBoughtSKU:
+		if _, err := strconv.ParseFloat(f.Value, 64); err != nil {
+			return fmt.Errorf("filter field %q requires a numeric value, got %q", f.Field, f.Value)
+		}
+	case FilterFieldTag:
+		if f.Relation == FilterRelationTimeElapsedGreaterThan || f.Relation == FilterRelationTimeElapsedLessThan {
+			if _, err := strconv.ParseFloat(f.Value, 64); err != nil {
+				return fmt.Errorf("filter relation %q requires a number of seconds, got %q", f.Relation, f.Value)
+			}
+		}
+	case FilterFieldLanguage, FilterFieldAppVersion, FilterFieldEmail, FilterFieldCountry:
+		if f.Value == "" {
+			return fmt.Errorf("filter field %q requires a value", f.Field)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks each filter and that operators separate conditions
+func (fs Filters) Validate() error {
+	if len(fs) > maxFilters {
+		return fmt.Errorf("filters can't have more than %d entries, got %d", maxFilters, len(fs))
+	}
+
+	for i, f := range fs {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("filters[%d]: %v", i, err)
+		}
+		if f.isOperator() && (i == 0 || i == len(fs)-1 || fs[i-1].isOperator()) {
+			return fmt.Errorf("filters[%d]: operator %q must be between two conditions", i, f.Operator)
+		}
+	}
+
+	return nil
+}
+
+func containsRelation(relations []FilterRelation, relation FilterRelation) bool {
+	for _, r := range relations {
+		if r == relation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package onesignal
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFilters_MarshalJSON(t *testing.T) {
+	filters := Filters{
+		FilterTag("level", FilterRelationGreaterThan, "10"),
+		FilterTag("vip", FilterRelationExists, ""),
+		FilterLastSession(FilterRelationLessThan, 1.5),
+		FilterFirstSession(FilterRelationGreaterThan, 24),
+		FilterSessionCount(FilterRelationEqual, 0),
+		FilterSessionTime(FilterRelationGreaterThan, 3600),
+		FilterAmountSpent(FilterRelationGreaterThan, 0.99),
+		FilterBoughtSKU("com.example.sku", FilterRelationGreaterThan, 2),
+		FilterLanguage(FilterRelationEqual, "en"),
+		FilterAppVersion(FilterRelationNotEqual, "1.0.0"),
+		FilterOr(),
+		FilterLocation(1000, 37.563, 122.3255),
+		FilterEmail("foo@example.com"),
+		FilterCountry("US"),
+	}
+
+	want := `[` +
+		`{"field":"tag","key":"level","relation":">","value":"10"},` +
+		`{"field":"tag","key":"vip","relation":"exists"},` +
+		`{"field":"last_session","relation":"<","hours_ago":"1.5"},` +
+		`{"field":"first_session","relation":">","hours_ago":"24"},` +
+		`{"field":"session_count","relation":"=","value":"0"},` +
+		`{"field":"session_time","relation":">","value":"3600"},` +
+		`{"field":"amount_spent","relation":">","value":"0.99"},` +
+		`{"field":"bought_sku","key":"com.example.sku","relation":">","value":"2"},` +
+		`{"field":"language","relation":"=","value":"en"},` +
+		`{"field":"app_version","relation":"!=","value":"1.0.0"},` +
+		`{"operator":"OR"},` +
+		`{"field":"location","radius":"1000","lat":"37.563","long":"122.3255"},` +
+		`{"field":"email","value":"foo@example.com"},` +
+		`{"field":"country","relation":"=","value":"US"}` +
+		`]`
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(filters); err != nil {
+		t.Fatalf("Encode returned an error: %v", err)
+	}
+	if got := strings.TrimSpace(b.String()); got != want {
+		t.Errorf("Filters JSON:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := filters.Validate(); err != nil {
+		t.Errorf("Validate returned unexpected error: %v", err)
+	}
+}
+
+func TestFilters_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		filters Filters
+		want    string
+	}{
+		{Filters{{Field: "levle", Relation: FilterRelationEqual, Value: "1"}}, `unknown filter field "levle"`},
+		{Filters{FilterLanguage(FilterRelationGreaterThan, "en")}, `filter field "language" doesn't support relation ">"`},
+		{Filters{FilterTag("", FilterRelationEqual, "1")}, `filter field "tag" requires a key`},
+		{Filters{FilterTag("ts", FilterRelationTimeElapsedGreaterThan, "yesterday")}, `filter relation "time_elapsed_gt" requires a number of seconds`},
+		{Filters{{Field: FilterFieldSessionCount, Relation: FilterRelationEqual, Value: "many"}}, `filter field "session_count" requires a numeric value`},
+		{Filters{{Field: FilterFieldEmail, Relation: FilterRelationEqual, Value: "foo@example.com"}}, `filter field "email" doesn't support relations`},
+		{Filters{{Field: FilterFieldLocation, Radius: "10"}}, `filter field "location" requires a numeric`},
+		{Filters{FilterOr(), FilterCountry("US")}, `filters[0]: operator "OR" must be between two conditions`},
+		{Filters{FilterCountry("US"), FilterOr(), FilterOr(), FilterCountry("VN")}, `filters[2]: operator "OR" must be between two conditions`},
+		{Filters{FilterCountry("US"), {Operator: "XOR"}, FilterCountry("VN")}, `unknown filter operator "XOR"`},
+		{make(Filters, maxFilters+1), `filters can't have more than 200 entries`},
+	} {
+		err := tc.filters.Validate()
+		if err == nil {
+			t.Errorf("Validate(%+v) should return an error", tc.filters)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Validate error is %q, want %q", err.Error(), tc.want)
+		}
+	}
+}
+
+func TestNotificationsService_Create_filters(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Filters []map[string]string `json:"filters"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		want := []map[string]string{
+			{"field": "tag", "key": "level", "relation": ">", "value": "10"},
+			{"operator": "OR"},
+			{"field": "country", "relation": "=", "value": "US"},
+		}
+		if len(body.Filters) != len(want) {
+			t.Fatalf("Filters: %v, want %v", body.Filters, want)
+		}
+		for i := range want {
+			for k, v := range want[i] {
+				if body.Filters[i][k] != v {
+					t.Errorf("Filters[%d][%s]: %v, want %v", i, k, body.Filters[i][k], v)
+				}
+			}
+		}
+
+		w.Write([]byte(`{"id":"notif-fake-id","recipients":1}`))
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{
+		Contents: map[string]string{"en": "English message"},
+		Filters: Filters{
+			FilterTag("level", FilterRelationGreaterThan, "10"),
+			FilterOr(),
+			FilterCountry("US"),
+		},
+	})
+	if err != nil {
+		t.Errorf("Create returned unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NotificationHistoryEvent is the kind of event exported by NotificationsService.History
+type NotificationHistoryEvent string
+
+const (
+	// Recipients the notification was sent to
+	NotificationHistoryEventSent NotificationHistoryEvent = "sent"
+	// Recipients who clicked the notification
+	NotificationHistoryEventClicked NotificationHistoryEvent = "clicked"
+)
+
+// NotificationHistoryRequest represents a request to export the history of a notification
+type NotificationHistoryRequest struct {
+	AppID  string                   `json:"app_id"`
+	Events NotificationHistoryEvent `json:"events"`
+	// Email address notified when the export is ready
+	Email string `json:"email,omitempty"`
+}
+
+// NotificationHistoryResponse wraps the standard http.Response for the
+// NotificationsService.History method
+type NotificationHistoryResponse struct {
+	Success bool `json:"success"`
+	// URL of the CSV file, available once the export is done
+	DestinationURL string `json:"destination_url"`
+}
+
+// NotificationHistoryRow is a row of the history export of a notification
+type NotificationHistoryRow struct {
+	PlayerID       string
+	ExternalUserID string
+	DeviceType     DeviceType
+	// Unix timestamp of the event
+	Time int
+}
+
+// History exports the recipients who received or clicked a notification, in the last 30 days.
+// The returned URL points to a CSV file which is generated asynchronously; use OpenHistory to read it.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/notification-history
+func (s *NotificationsService) History(notificationID string, event NotificationHistoryEvent, email string) (*NotificationHistoryResponse, *http.Response, error) {
+	return s.HistoryContext(context.Background(), notificationID, event, email)
+}
+
+// HistoryContext exports the history of a notification with the provided context.
+func (s *NotificationsService) HistoryContext(ctx context.Context, notificationID string, event NotificationHistoryEvent, email string) (*NotificationHistoryResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/notifications/%s/history", notificationID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), &NotificationHistoryRequest{
+		AppID:  s.client.appID,
+		Events: event,
+		Email:  email,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	historyRes := &NotificationHistoryResponse{}
+	resp, err := s.client.Do(req, historyRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return historyRes, resp, err
+}
+
+// NotificationHistoryIterator streams the rows of the history export of a notification.
+type NotificationHistoryIterator struct {
+	csv     *csvIterator
+	current NotificationHistoryRow
+}
+
+// DownloadHistory exports the history of a notification, waits until the file is available,
+// and returns an iterator over its rows.
+func (s *NotificationsService) DownloadHistory(ctx context.Context, notificationID string, event NotificationHistoryEvent, email string, dlOpt ...CSVDownloadOptions) (*NotificationHistoryIterator, error) {
+	res, _, err := s.HistoryContext(ctx, notificationID, event, email)
+	if err != nil {
+		return nil, err
+	}
+	return s.OpenHistory(ctx, res.DestinationURL, dlOpt...)
+}
+
+// OpenHistory waits until the history export at fileURL is available and returns an iterator over its rows.
+func (s *NotificationsService) OpenHistory(ctx context.Context, fileURL string, dlOpt ...CSVDownloadOptions) (*NotificationHistoryIterator, error) {
+	it, err := s.client.openCSV(ctx, fileURL, dlOpt)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationHistoryIterator{csv: it}, nil
+}
+
+// Next reads the next row. It returns false at the end of the file or when an error occurred.
+func (it *NotificationHistoryIterator) Next() bool {
+	return it.csv.next(func(p *csvParser) {
+		it.current = NotificationHistoryRow{
+			PlayerID:       p.string(p.rows.column("player_id", "id")),
+			ExternalUserID: p.string(p.rows.column("external_user_id", "external_id")),
+			DeviceType:     DeviceType(p.int("device_type")),
+			Time:           p.unix(p.rows.column("time", "timestamp")),
+		}
+	})
+}
+
+// Row returns the current row
+func (it *NotificationHistoryIterator) Row() NotificationHistoryRow {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *NotificationHistoryIterator) Err() error {
+	return it.csv.err
+}
+
+// Close closes the downloaded file
+func (it *NotificationHistoryIterator) Close() error {
+	return it.csv.body.Close()
+}
@@ -0,0 +1,95 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotificationsService_History(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	requestSent := false
+
+	mux.HandleFunc("/notifications/notif-fake-id/history", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+		testBody(t, r, &NotificationHistoryRequest{}, &NotificationHistoryRequest{
+			AppID:  client.appID,
+			Events: NotificationHistoryEventClicked,
+			Email:  "audit@example.com",
+		})
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"destination_url": "https://onesignal-files.example.com/history.csv"
+		}`)
+	})
+
+	res, _, err := client.Notifications.History("notif-fake-id", NotificationHistoryEventClicked, "audit@example.com")
+	if err != nil {
+		t.Errorf("History returned an error: %v", err)
+	}
+
+	want := &NotificationHistoryResponse{
+		Success:        true,
+		DestinationURL: "https://onesignal-files.example.com/history.csv",
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("History returned %+v, want %+v", res, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestNotificationsService_DownloadHistory(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	attempts := 0
+	fileServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(gzipString(t, "player_id,external_user_id,device_type,time\nplayer-1,user-1,0,2021-01-19 23:15:43\nplayer-2,,1,1611098143\n"))
+	}))
+	defer fileServer.Close()
+
+	mux.HandleFunc("/notifications/notif-fake-id/history", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"success": true, "destination_url": "%s/history.csv.gz"}`, fileServer.URL)
+	})
+
+	it, err := client.Notifications.DownloadHistory(context.Background(), "notif-fake-id", NotificationHistoryEventSent, "",
+		CSVDownloadOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("DownloadHistory returned an error: %v", err)
+	}
+	defer it.Close()
+
+	var rows []NotificationHistoryRow
+	for it.Next() {
+		rows = append(rows, it.Row())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iteration returned an error: %v", err)
+	}
+
+	want := []NotificationHistoryRow{
+		{PlayerID: "player-1", ExternalUserID: "user-1", DeviceType: 0, Time: 1611098143},
+		{PlayerID: "player-2", DeviceType: 1, Time: 1611098143},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Rows: %+v, want %+v", rows, want)
+	}
+}
@@ -0,0 +1,48 @@
+package onesignal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// IdentityHash returns the identity verification hash of a value,
+// the hex encoded HMAC-SHA256 of the value with the REST API key.
+// https://documentation.onesignal.com/docs/identity-verification
+func IdentityHash(apiKey, value string) string {
+	mac := hmac.New(sha256.New, []byte(apiKey))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetIdentityVerification enables computing the identity verification hashes of player requests
+// with the API key of the client: IdentifierAuthHash of email and SMS devices, ExternalUserIDAuthHash,
+// and EmailAuthHash of PlayerGetOptions with an Email. Hashes which are already set are kept.
+//
+// Notification requests are authenticated by the REST API key and don't take hashes,
+// so they are sent unchanged.
+func (c *Client) SetIdentityVerification(enabled bool) {
+	c.identityVerification = enabled
+}
+
+// signPlayerRequest sets the missing identity verification hashes of the player request
+func (c *Client) signPlayerRequest(player *PlayerRequest) {
+	if !c.identityVerification {
+		return
+	}
+	if player.IdentifierAuthHash == "" && player.Identifier != "" &&
+		(player.DeviceType == DeviceTypeEmail || player.DeviceType == DeviceTypeSMS) {
+		player.IdentifierAuthHash = IdentityHash(c.apiKey, player.Identifier)
+	}
+	if player.ExternalUserIDAuthHash == "" && player.ExternalUserID != "" {
+		player.ExternalUserIDAuthHash = IdentityHash(c.apiKey, player.ExternalUserID)
+	}
+}
+
+// emailAuthHash returns the email_auth_hash of the get options
+func (c *Client) emailAuthHash(opt PlayerGetOptions) string {
+	if opt.EmailAuthHash == "" && opt.Email != "" && c.identityVerification {
+		return IdentityHash(c.apiKey, opt.Email)
+	}
+	return opt.EmailAuthHash
+}
@@ -0,0 +1,85 @@
+package onesignal
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIdentityHash(t *testing.T) {
+	got := IdentityHash("key", "The quick brown fox jumps over the lazy dog")
+	if want := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"; got != want {
+		t.Errorf("IdentityHash: %v, want %v", got, want)
+	}
+}
+
+func TestPlayersService_Create_identityVerification(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetIdentityVerification(true)
+
+	player := PlayerRequest{
+		DeviceType:     DeviceTypeEmail,
+		Identifier:     "foo@example.com",
+		ExternalUserID: "user-id",
+	}
+
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		want := player
+		want.IdentifierAuthHash = IdentityHash(client.apiKey, "foo@example.com")
+		want.ExternalUserIDAuthHash = IdentityHash(client.apiKey, "user-id")
+		testBody(t, r, &PlayerRequest{}, &want)
+
+		fmt.Fprint(w, `{"success": true, "id": "player-fake-id"}`)
+	})
+
+	if _, _, err := client.Players.Create(player); err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+	if player.IdentifierAuthHash != "" {
+		t.Errorf("Create should not modify the player request")
+	}
+}
+
+func TestPlayersService_Update_identityVerificationPush(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetIdentityVerification(true)
+
+	player := PlayerRequest{
+		DeviceType:             DeviceTypeIOS,
+		Identifier:             "push-token",
+		ExternalUserID:         "user-id",
+		ExternalUserIDAuthHash: "precomputed-hash",
+	}
+
+	mux.HandleFunc("/players/player-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		// push tokens aren't hashed and existing hashes are kept
+		testBody(t, r, &PlayerRequest{}, &player)
+
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	if _, _, err := client.Players.Update("player-fake-id", player); err != nil {
+		t.Errorf("Update returned an error: %v", err)
+	}
+}
+
+func TestPlayersService_Get_identityVerification(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/players/player-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("email_auth_hash"), IdentityHash(client.apiKey, "foo@example.com"); got != want {
+			t.Errorf("email_auth_hash: %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"id": "player-fake-id"}`)
+	})
+
+	client.SetIdentityVerification(true)
+	if _, _, err := client.Players.Get("player-fake-id", PlayerGetOptions{Email: "foo@example.com"}); err != nil {
+		t.Errorf("Get returned an error: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Attribute is a key/value pair describing a span or a measurement
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attribute keys of the spans and metrics
+const (
+	AttributeOperation  = "onesignal.operation"
+	AttributeAppID      = "onesignal.app_id"
+	AttributeAttempts   = "onesignal.attempts"
+	AttributeRecipients = "onesignal.recipients"
+	AttributeMethod     = "http.method"
+	AttributeStatusCode = "http.status_code"
+	// Source of a rate limit hit: "server" for 429 responses, "client" for the client side rate limiter
+	AttributeRateLimitSource = "onesignal.rate_limit.source"
+)
+
+// Metric names
+const (
+	// Histogram of the duration of the API calls, including retries
+	MetricDuration = "onesignal.client.duration"
+	// Counter of the failed API calls
+	MetricErrors = "onesignal.client.errors"
+	// Counter of the requests rejected by a rate limit
+	MetricRateLimited = "onesignal.client.rate_limited"
+)
+
+// Tracer starts the spans of the API calls. It can be implemented with an OpenTelemetry tracer.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span traces an API call
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Meter records the metrics of the API calls. It can be implemented with OpenTelemetry instruments.
+type Meter interface {
+	// RecordDuration records a duration in a histogram
+	RecordDuration(ctx context.Context, name string, d time.Duration, attrs ...Attribute)
+	// AddCount adds n to a counter
+	AddCount(ctx context.Context, name string, n int64, attrs ...Attribute)
+}
+
+// Instrumentation specifies the tracer and the meter of a client. Nil values disable them.
+type Instrumentation struct {
+	Tracer Tracer
+	Meter  Meter
+}
+
+// SetInstrumentation sets the tracer and the meter of the API calls
+func (c *httpClient) SetInstrumentation(instrumentation Instrumentation) {
+	if instrumentation.Tracer == nil {
+		instrumentation.Tracer = noopTracer{}
+	}
+	if instrumentation.Meter == nil {
+		instrumentation.Meter = noopMeter{}
+	}
+	c.instrumentation = instrumentation
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+type noopMeter struct{}
+
+func (noopMeter) RecordDuration(ctx context.Context, name string, d time.Duration, attrs ...Attribute) {
+}
+func (noopMeter) AddCount(ctx context.Context, name string, n int64, attrs ...Attribute) {}
+
+// callStats counts the attempts of an API call
+type callStats struct {
+	attempts    int
+	rateLimited map[string]int64
+}
+
+type callStatsContextKey struct{}
+
+// recordAttempt updates the stats of the API call of the request, if any
+func recordAttempt(r *http.Request, resp *http.Response, err error) {
+	stats, ok := r.Context().Value(callStatsContextKey{}).(*callStats)
+	if !ok {
+		return
+	}
+	stats.attempts++
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		stats.rateLimited["client"]++
+	case err == nil && resp.StatusCode == http.StatusTooManyRequests:
+		stats.rateLimited["server"]++
+	}
+}
+
+// instrument traces and measures an API call
+func (c *httpClient) instrument(r *http.Request, v interface{}, call func(r *http.Request) (*http.Response, error)) (*http.Response, error) {
+	op := c.operationOf(r)
+	name := string(op)
+	if op == OperationUnknown {
+		name = "onesignal.request"
+	}
+
+	ctx, span := c.instrumentation.Tracer.Start(r.Context(), name)
+	defer span.End()
+
+	stats := &callStats{rateLimited: map[string]int64{}}
+	ctx = context.WithValue(ctx, callStatsContextKey{}, stats)
+
+	start := time.Now()
+	resp, err := call(r.WithContext(ctx))
+	duration := time.Since(start)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	} else if apiErr, ok := AsAPIError(err); ok {
+		status = apiErr.StatusCode
+	}
+
+	attrs := withAttributes(c.attributes, Attribute{AttributeOperation, string(op)})
+	span.SetAttributes(withAttributes(attrs,
+		Attribute{AttributeMethod, r.Method},
+		Attribute{AttributeStatusCode, status},
+		Attribute{AttributeAttempts, stats.attempts},
+	)...)
+	if res, ok := v.(*NotificationCreateResponse); ok && err == nil {
+		span.SetAttributes(Attribute{AttributeRecipients, res.Recipients})
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	meter := c.instrumentation.Meter
+	metricAttrs := withAttributes(attrs, Attribute{AttributeStatusCode, status})
+	meter.RecordDuration(ctx, MetricDuration, duration, metricAttrs...)
+	if err != nil {
+		meter.AddCount(ctx, MetricErrors, 1, metricAttrs...)
+	}
+	for source, n := range stats.rateLimited {
+		meter.AddCount(ctx, MetricRateLimited, n, withAttributes(attrs, Attribute{AttributeRateLimitSource, source})...)
+	}
+
+	return resp, err
+}
+
+// withAttributes returns a new slice with the attributes and the extra ones
+func withAttributes(attrs []Attribute, extra ...Attribute) []Attribute {
+	res := make([]Attribute, 0, len(attrs)+len(extra))
+	return append(append(res, attrs...), extra...)
+}
@@ -0,0 +1,153 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testExporter records the spans and the metrics of a client
+type testExporter struct {
+	mu       sync.Mutex
+	spans    []*testSpan
+	counts   map[string][]testMeasurement
+	duration map[string][]testMeasurement
+}
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+type testMeasurement struct {
+	value interface{}
+	attrs map[string]interface{}
+}
+
+func newTestExporter() *testExporter {
+	return &testExporter{counts: map[string][]testMeasurement{}, duration: map[string][]testMeasurement{}}
+}
+
+func attributeMap(attrs []Attribute) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, a := range attrs {
+		m[a.Key] = a.Value
+	}
+	return m
+}
+
+func (e *testExporter) Start(ctx context.Context, name string) (context.Context, Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	span := &testSpan{name: name, attrs: map[string]interface{}{}}
+	e.spans = append(e.spans, span)
+	return ctx, span
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	for k, v := range attributeMap(attrs) {
+		s.attrs[k] = v
+	}
+}
+
+func (s *testSpan) RecordError(err error) { s.err = err }
+func (s *testSpan) End()                  { s.ended = true }
+
+func (e *testExporter) RecordDuration(ctx context.Context, name string, d time.Duration, attrs ...Attribute) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.duration[name] = append(e.duration[name], testMeasurement{d, attributeMap(attrs)})
+}
+
+func (e *testExporter) AddCount(ctx context.Context, name string, n int64, attrs ...Attribute) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.counts[name] = append(e.counts[name], testMeasurement{n, attributeMap(attrs)})
+}
+
+func TestClient_SetInstrumentation(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	exporter := newTestExporter()
+	client.SetInstrumentation(Instrumentation{Tracer: exporter, Meter: exporter})
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond})
+
+	attempts := 0
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id": "notif-id", "recipients": 3}`)
+	})
+
+	req := *sampleNotificationRequest
+	req.ExternalID = "b8e6a7e4-1b7b-4c9b-9c6e-2d3f4e5a6b7c"
+	if _, _, err := client.Notifications.Create(&req); err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	if len(exporter.spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(exporter.spans))
+	}
+	span := exporter.spans[0]
+	wantAttrs := map[string]interface{}{
+		AttributeOperation:  "notifications.create",
+		AttributeAppID:      "fake-app-id",
+		AttributeMethod:     "POST",
+		AttributeStatusCode: http.StatusOK,
+		AttributeAttempts:   2,
+		AttributeRecipients: 3,
+	}
+	if span.name != "notifications.create" || !span.ended || span.err != nil || !reflect.DeepEqual(span.attrs, wantAttrs) {
+		t.Errorf("span: %+v, want attributes %v", span, wantAttrs)
+	}
+
+	durations := exporter.duration[MetricDuration]
+	if len(durations) != 1 || durations[0].attrs[AttributeStatusCode] != http.StatusOK {
+		t.Errorf("durations: %+v", durations)
+	}
+	if len(exporter.counts) != 0 {
+		t.Errorf("counts: %+v, want none", exporter.counts)
+	}
+}
+
+func TestClient_SetInstrumentation_errors(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	exporter := newTestExporter()
+	client.SetInstrumentation(Instrumentation{Tracer: exporter, Meter: exporter})
+
+	mux.HandleFunc("/players/player-id", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"errors": ["API rate limit exceeded"]}`)
+	})
+
+	_, _, err := client.Players.Get("player-id")
+	if !IsRateLimited(err) {
+		t.Fatalf("Get returned %v, want a rate limit error", err)
+	}
+
+	span := exporter.spans[0]
+	if span.err != err || span.attrs[AttributeStatusCode] != http.StatusTooManyRequests || span.attrs[AttributeAttempts] != 1 {
+		t.Errorf("span: %+v", span)
+	}
+
+	errorCounts := exporter.counts[MetricErrors]
+	if len(errorCounts) != 1 || errorCounts[0].attrs[AttributeStatusCode] != http.StatusTooManyRequests ||
+		errorCounts[0].attrs[AttributeOperation] != "players.get" {
+		t.Errorf("errors: %+v", errorCounts)
+	}
+	rateLimited := exporter.counts[MetricRateLimited]
+	if len(rateLimited) != 1 || rateLimited[0].value != int64(1) || rateLimited[0].attrs[AttributeRateLimitSource] != "server" {
+		t.Errorf("rate limited: %+v", rateLimited)
+	}
+}
@@ -0,0 +1,262 @@
+package onesignal
+
+import (
+	"context"
+)
+
+const (
+	// Maximum number of players returned by a single PlayersService.List request
+	maxPlayersPageSize = 300
+	// Maximum number of notifications returned by a single NotificationsService.List request
+	maxNotificationsPageSize = 50
+)
+
+// pageFetcher fetches a page of items and returns the number of items of the page
+// and the total count of items
+type pageFetcher func(ctx context.Context, limit, offset int) (n int, total int, err error)
+
+// pager walks the pages of a list endpoint until the total count is reached
+type pager struct {
+	ctx    context.Context
+	limit  int
+	offset int
+	fetch  pageFetcher
+	done   bool
+	err    error
+}
+
+func newPager(ctx context.Context, limit, offset, maxLimit int, fetch pageFetcher) *pager {
+	if limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return &pager{
+		ctx:    ctx,
+		limit:  limit,
+		offset: offset,
+		fetch:  fetch,
+	}
+}
+
+// check records the context error, if any, and reports whether the iteration can continue
+func (p *pager) check() bool {
+	if p.err == nil {
+		p.err = p.ctx.Err()
+	}
+	return p.err == nil
+}
+
+// next fetches the next page. It returns false when there are no more items or an error occurred.
+func (p *pager) next() bool {
+	if p.done || !p.check() {
+		return false
+	}
+
+	n, total, err := p.fetch(p.ctx, p.limit, p.offset)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.offset += n
+	if n == 0 || p.offset >= total {
+		p.done = true
+	}
+
+	return n > 0
+}
+
+// PlayerIterator iterates over the players of an app, fetching the pages on demand.
+//
+//	it := client.Players.All(ctx, nil)
+//	for it.Next() {
+//		player := it.Player()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type PlayerIterator struct {
+	pager   *pager
+	page    []Player
+	index   int
+	current Player
+}
+
+// All returns an iterator over all the players, starting at the offset of opt.
+// The page size is opt.Limit, capped at the API limit of 300.
+func (s *PlayersService) All(ctx context.Context, opt *PlayerListOptions) *PlayerIterator {
+	if opt == nil {
+		opt = &PlayerListOptions{}
+	}
+
+	it := &PlayerIterator{}
+	it.pager = newPager(ctx, opt.Limit, opt.Offset, maxPlayersPageSize, func(ctx context.Context, limit, offset int) (int, int, error) {
+		res, _, err := s.ListContext(ctx, &PlayerListOptions{Limit: limit, Offset: offset})
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Players
+		it.index = 0
+		return len(res.Players), res.TotalCount, nil
+	})
+
+	return it
+}
+
+// Each calls fn for each player until all players are visited, fn returns an error or the context is done.
+func (s *PlayersService) Each(ctx context.Context, opt *PlayerListOptions, fn func(Player) error) error {
+	it := s.All(ctx, opt)
+	for it.Next() {
+		if err := fn(it.Player()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Next advances the iterator to the next player. It returns false when the iteration stops,
+// either because all players were visited or an error occurred.
+func (it *PlayerIterator) Next() bool {
+	if !it.pager.check() || it.index >= len(it.page) && !it.pager.next() {
+		return false
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Player returns the current player
+func (it *PlayerIterator) Player() Player {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *PlayerIterator) Err() error {
+	return it.pager.err
+}
+
+// NotificationIterator iterates over the notifications of an app, fetching the pages on demand.
+type NotificationIterator struct {
+	pager   *pager
+	page    []Notification
+	index   int
+	current Notification
+}
+
+// All returns an iterator over all the notifications, starting at the offset of opt.
+// The page size is opt.Limit, capped at the API limit of 50.
+func (s *NotificationsService) All(ctx context.Context, opt ...NotificationListOptions) *NotificationIterator {
+	var op NotificationListOptions
+	if len(opt) > 0 {
+		op = opt[0]
+	}
+
+	it := &NotificationIterator{}
+	it.pager = newPager(ctx, op.Limit, op.Offset, maxNotificationsPageSize, func(ctx context.Context, limit, offset int) (int, int, error) {
+		res, _, err := s.ListContext(ctx, NotificationListOptions{Limit: limit, Offset: offset, Kind: op.Kind})
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = res.Notifications
+		it.index = 0
+		return len(res.Notifications), res.TotalCount, nil
+	})
+
+	return it
+}
+
+// Each calls fn for each notification until all notifications are visited, fn returns an error or the context is done.
+func (s *NotificationsService) Each(ctx context.Context, fn func(Notification) error, opt ...NotificationListOptions) error {
+	it := s.All(ctx, opt...)
+	for it.Next() {
+		if err := fn(it.Notification()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Next advances the iterator to the next notification. It returns false when the iteration stops,
+// either because all notifications were visited or an error occurred.
+func (it *NotificationIterator) Next() bool {
+	if !it.pager.check() || it.index >= len(it.page) && !it.pager.next() {
+		return false
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Notification returns the current notification
+func (it *NotificationIterator) Notification() Notification {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *NotificationIterator) Err() error {
+	return it.pager.err
+}
+
+// AppIterator iterates over the apps of the user.
+// The apps endpoint isn't paginated so all apps are fetched by the first call to Next.
+type AppIterator struct {
+	pager   *pager
+	page    []App
+	index   int
+	current App
+}
+
+// All returns an iterator over all the apps
+func (s *AppsService) All(ctx context.Context) *AppIterator {
+	it := &AppIterator{}
+	it.pager = newPager(ctx, 0, 0, 1, func(ctx context.Context, limit, offset int) (int, int, error) {
+		apps, _, err := s.ListContext(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = apps
+		it.index = 0
+		return len(apps), len(apps), nil
+	})
+
+	return it
+}
+
+// Each calls fn for each app until all apps are visited, fn returns an error or the context is done.
+func (s *AppsService) Each(ctx context.Context, fn func(App) error) error {
+	it := s.All(ctx)
+	for it.Next() {
+		if err := fn(it.App()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Next advances the iterator to the next app. It returns false when the iteration stops,
+// either because all apps were visited or an error occurred.
+func (it *AppIterator) Next() bool {
+	if !it.pager.check() || it.index >= len(it.page) && !it.pager.next() {
+		return false
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// App returns the current app
+func (it *AppIterator) App() App {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *AppIterator) Err() error {
+	return it.pager.err
+}
@@ -0,0 +1,188 @@
+package onesignal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// handlePlayerPages serves count players with ids from 0 to count-1
+func handlePlayerPages(t *testing.T, mux *http.ServeMux, count int, offsets *[]int) {
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		*offsets = append(*offsets, offset)
+
+		res := PlayerListResponse{TotalCount: count, Offset: offset, Limit: limit, Players: []Player{}}
+		for i := offset; i < count && i < offset+limit; i++ {
+			res.Players = append(res.Players, Player{ID: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+func TestPlayersService_All(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	var offsets []int
+	handlePlayerPages(t, mux, 5, &offsets)
+
+	var ids []string
+	it := client.Players.All(context.Background(), &PlayerListOptions{Limit: 2})
+	for it.Next() {
+		ids = append(ids, it.Player().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err returned unexpected error: %v", err)
+	}
+
+	if want := []string{"0", "1", "2", "3", "4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Players: %v, want %v", ids, want)
+	}
+
+	if want := []int{0, 2, 4}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("Offsets: %v, want %v", offsets, want)
+	}
+}
+
+func TestPlayersService_All_maxPageSize(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	var offsets []int
+	handlePlayerPages(t, mux, 301, &offsets)
+
+	count := 0
+	err := client.Players.Each(context.Background(), &PlayerListOptions{Limit: 1000}, func(p Player) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each returned unexpected error: %v", err)
+	}
+
+	if count != 301 {
+		t.Errorf("Players: %d, want %d", count, 301)
+	}
+
+	if want := []int{0, 300}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("Offsets: %v, want %v", offsets, want)
+	}
+}
+
+func TestPlayersService_All_contextCanceled(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	var offsets []int
+	handlePlayerPages(t, mux, 5, &offsets)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count := 0
+	err := client.Players.Each(ctx, &PlayerListOptions{Limit: 2}, func(p Player) error {
+		count++
+		if count == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("Each error is %v, want %v", err, context.Canceled)
+	}
+
+	if count != 3 {
+		t.Errorf("Players: %d, want %d", count, 3)
+	}
+}
+
+func TestPlayersService_Each_callbackError(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	var offsets []int
+	handlePlayerPages(t, mux, 5, &offsets)
+
+	stop := errors.New("stop")
+	err := client.Players.Each(context.Background(), nil, func(p Player) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("Each error is %v, want %v", err, stop)
+	}
+}
+
+func TestNotificationsService_All(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	kind := NotificationKindAPI
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+
+		if got, want := r.URL.Query().Get("kind"), "1"; got != want {
+			t.Errorf("Kind: %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("limit"), "50"; got != want {
+			t.Errorf("Limit: %v, want %v", got, want)
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset > 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		res := NotificationListResponse{TotalCount: 60, Notifications: make([]Notification, 50)}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	count := 0
+	it := client.Notifications.All(context.Background(), NotificationListOptions{Kind: &kind})
+	for it.Next() {
+		count++
+	}
+
+	if count != 50 {
+		t.Errorf("Notifications: %d, want %d", count, 50)
+	}
+
+	if !IsStatus(it.Err(), http.StatusInternalServerError) {
+		t.Errorf("Err is %v, want status %d", it.Err(), http.StatusInternalServerError)
+	}
+}
+
+func TestAppsService_Each(t *testing.T) {
+	server, mux, client := setupUserClient(t)
+	defer teardown(server)
+
+	requests := 0
+	mux.HandleFunc("/apps", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `[{"id":"app-1"},{"id":"app-2"}]`)
+	})
+
+	var ids []string
+	err := client.Apps.Each(context.Background(), func(app App) error {
+		ids = append(ids, app.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each returned unexpected error: %v", err)
+	}
+
+	if want := []string{"app-1", "app-2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Apps: %v, want %v", ids, want)
+	}
+
+	if requests != 1 {
+		t.Errorf("Requests: %d, want %d", requests, 1)
+	}
+}
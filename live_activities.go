@@ -0,0 +1,156 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// LiveActivityEvent is the action applied to a Live Activity
+type LiveActivityEvent string
+
+// LiveActivityPriority is the APNs priority of a Live Activity update
+type LiveActivityPriority int
+
+const (
+	// Update the content of the Live Activity
+	LiveActivityEventUpdate LiveActivityEvent = "update"
+	// End the Live Activity
+	LiveActivityEventEnd LiveActivityEvent = "end"
+
+	// Low priority updates are throttled by iOS to save battery
+	LiveActivityPriorityLow LiveActivityPriority = 5
+	// High priority updates are delivered immediately, within the budget set by iOS
+	LiveActivityPriorityHigh LiveActivityPriority = 10
+)
+
+// LiveActivitiesService handles communication with the iOS Live Activities related
+// methods of the OneSignal API.
+type LiveActivitiesService struct {
+	client *Client
+}
+
+// LiveActivityRequest represents a request to update or end a Live Activity.
+// https://documentation.onesignal.com/docs/live-activities
+type LiveActivityRequest struct {
+	// An identifier for tracking message within the OneSignal dashboard. Required.
+	Name string `json:"name"`
+	// Update or end the Live Activity. Required.
+	Event LiveActivityEvent `json:"event"`
+	// Content state of the Live Activity, matching the ContentState of your ActivityAttributes. Required.
+	EventUpdates interface{} `json:"event_updates"`
+	// Alert shown along with the update, a map of language codes to text for each language.
+	Contents map[string]string `json:"contents,omitempty"`
+	// Title of the alert, a map of language codes to text for each language.
+	Headings map[string]string `json:"headings,omitempty"`
+	// Sound file played with the alert
+	Sound string `json:"sound,omitempty"`
+	// Unix timestamp after which the Live Activity is marked as outdated
+	StaleDate int64 `json:"stale_date,omitempty"`
+	// Unix timestamp when the ended Live Activity is removed from the lock screen. Only used with the end event.
+	DismissalDate int64 `json:"dismissal_date,omitempty"`
+	// APNs priority of the update. Defaults to low priority.
+	Priority LiveActivityPriority `json:"priority,omitempty"`
+	// iOS 15+ Relevance Score to sort the Live Activities of the app
+	IOSRelevanceScore float32 `json:"ios_relevance_score,omitempty"`
+}
+
+// LiveActivityResponse wraps the standard http.Response for the
+// LiveActivitiesService.Update and LiveActivitiesService.End methods
+type LiveActivityResponse struct {
+	// ID of the notification sent to update the Live Activity
+	ID string `json:"id"`
+}
+
+// Validate checks the Live Activity request against the rules of the OneSignal API.
+// It returns ValidationErrors listing every violated rule, or nil.
+func (r *LiveActivityRequest) Validate() error {
+	var errs ValidationErrors
+	if r.Name == "" {
+		errs.add("name", "is required")
+	}
+	switch r.Event {
+	case LiveActivityEventUpdate:
+		if r.DismissalDate != 0 {
+			errs.add("dismissal_date", "can only be set when event is %q", LiveActivityEventEnd)
+		}
+	case LiveActivityEventEnd:
+	default:
+		errs.add("event", "must be %q or %q, got %q", LiveActivityEventUpdate, LiveActivityEventEnd, r.Event)
+	}
+	if r.EventUpdates == nil {
+		errs.add("event_updates", "is required")
+	}
+	if len(r.Contents) > 0 && r.Contents["en"] == "" {
+		errs.add("contents", "must include English (\"en\") text")
+	}
+	if r.Priority != 0 && r.Priority != LiveActivityPriorityLow && r.Priority != LiveActivityPriorityHigh {
+		errs.add("priority", "must be %d or %d, got %d", LiveActivityPriorityLow, LiveActivityPriorityHigh, r.Priority)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Send updates or ends a Live Activity, depending on the event of the request.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/update-live-activity-via-push
+func (s *LiveActivitiesService) Send(activityID string, opt *LiveActivityRequest) (*LiveActivityResponse, *http.Response, error) {
+	return s.SendContext(context.Background(), activityID, opt)
+}
+
+// SendContext updates or ends a Live Activity with the provided context.
+func (s *LiveActivitiesService) SendContext(ctx context.Context, activityID string, opt *LiveActivityRequest) (*LiveActivityResponse, *http.Response, error) {
+	if s.client.validateRequests {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/apps/%s/live_activities/%s/notifications", s.client.appID, url.PathEscape(activityID)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	laResp := &LiveActivityResponse{}
+	resp, err := s.client.Do(req, laResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return laResp, resp, err
+}
+
+// Update updates the content state of a Live Activity.
+func (s *LiveActivitiesService) Update(activityID string, opt *LiveActivityRequest) (*LiveActivityResponse, *http.Response, error) {
+	return s.UpdateContext(context.Background(), activityID, opt)
+}
+
+// UpdateContext updates the content state of a Live Activity with the provided context.
+func (s *LiveActivitiesService) UpdateContext(ctx context.Context, activityID string, opt *LiveActivityRequest) (*LiveActivityResponse, *http.Response, error) {
+	req := *opt
+	req.Event = LiveActivityEventUpdate
+	return s.SendContext(ctx, activityID, &req)
+}
+
+// End ends a Live Activity, with its final content state.
+func (s *LiveActivitiesService) End(activityID string, opt *LiveActivityRequest) (*LiveActivityResponse, *http.Response, error) {
+	return s.EndContext(context.Background(), activityID, opt)
+}
+
+// EndContext ends a Live Activity with the provided context.
+func (s *LiveActivitiesService) EndContext(ctx context.Context, activityID string, opt *LiveActivityRequest) (*LiveActivityResponse, *http.Response, error) {
+	req := *opt
+	req.Event = LiveActivityEventEnd
+	return s.SendContext(ctx, activityID, &req)
+}
@@ -0,0 +1,90 @@
+package onesignal
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestLiveActivitiesService_Update(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	requestSent := false
+
+	mux.HandleFunc("/apps/fake-app-id/live_activities/activity-fake-id/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+		testBody(t, r, &LiveActivityRequest{}, &LiveActivityRequest{
+			Name:         "Score update",
+			Event:        LiveActivityEventUpdate,
+			EventUpdates: map[string]interface{}{"home": float64(2), "away": float64(1)},
+			Contents:     map[string]string{"en": "Goal!"},
+			StaleDate:    1700000000,
+			Priority:     LiveActivityPriorityHigh,
+		})
+
+		fmt.Fprint(w, `{"id": "notif-fake-id"}`)
+	})
+
+	res, _, err := client.LiveActivities.Update("activity-fake-id", &LiveActivityRequest{
+		Name:         "Score update",
+		EventUpdates: map[string]interface{}{"home": 2, "away": 1},
+		Contents:     map[string]string{"en": "Goal!"},
+		StaleDate:    1700000000,
+		Priority:     LiveActivityPriorityHigh,
+	})
+	if err != nil {
+		t.Errorf("Update returned an error: %v", err)
+	}
+
+	if want := (&LiveActivityResponse{ID: "notif-fake-id"}); !reflect.DeepEqual(res, want) {
+		t.Errorf("Update returned %+v, want %+v", res, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestLiveActivitiesService_End(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/apps/fake-app-id/live_activities/activity-fake-id/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testBody(t, r, &LiveActivityRequest{}, &LiveActivityRequest{
+			Name:          "Final score",
+			Event:         LiveActivityEventEnd,
+			EventUpdates:  map[string]interface{}{"home": float64(3)},
+			DismissalDate: 1700003600,
+		})
+
+		fmt.Fprint(w, `{"id": "notif-fake-id"}`)
+	})
+
+	_, _, err := client.LiveActivities.End("activity-fake-id", &LiveActivityRequest{
+		Name:          "Final score",
+		EventUpdates:  map[string]interface{}{"home": 3},
+		DismissalDate: 1700003600,
+	})
+	if err != nil {
+		t.Errorf("End returned an error: %v", err)
+	}
+}
+
+func TestLiveActivityRequest_Validate(t *testing.T) {
+	err := (&LiveActivityRequest{
+		Event:         LiveActivityEventUpdate,
+		DismissalDate: 1700003600,
+		Contents:      map[string]string{"fr": "But !"},
+		Priority:      7,
+	}).Validate()
+
+	want := []string{"name", "dismissal_date", "event_updates", "contents", "priority"}
+	if got := validationFields(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate errors on %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogLevel is the severity of a log entry
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
+
+// Logger is a structured logger.
+// Fields are alternating keys and values, like "method", "GET", "status", 200.
+//
+// Entries are redacted before being logged: the API key, auth hashes,
+// email addresses and phone numbers never reach the logger.
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, fields ...interface{})
+}
+
+// SlogLogger is implemented by log/slog style loggers, like *slog.Logger
+type SlogLogger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// NewSlogLogger returns a Logger writing to a log/slog style logger
+func NewSlogLogger(logger SlogLogger) Logger {
+	return slogLogger{logger}
+}
+
+type slogLogger struct {
+	logger SlogLogger
+}
+
+func (l slogLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...interface{}) {
+	switch {
+	case level >= LogLevelError:
+		l.logger.ErrorContext(ctx, msg, fields...)
+	case level >= LogLevelWarn:
+		l.logger.WarnContext(ctx, msg, fields...)
+	case level >= LogLevelInfo:
+		l.logger.InfoContext(ctx, msg, fields...)
+	default:
+		l.logger.DebugContext(ctx, msg, fields...)
+	}
+}
+
+// funcLogger formats the entries for the loggers of SetLogger, like
+// "[OneSignal] response method=GET path=/players status=200"
+type funcLogger func(args ...interface{})
+
+func (l funcLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...interface{}) {
+	var b strings.Builder
+	b.WriteString("[OneSignal] ")
+	b.WriteString(msg)
+	for i := 0; i+1 < len(fields); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", fields[i], fields[i+1])
+	}
+	l(b.String())
+}
+
+// SetStructuredLogger sets the logger of the requests, responses and retries. A nil logger disables logging.
+func (c *httpClient) SetStructuredLogger(logger Logger) {
+	c.logger = logger
+}
+
+// log redacts and logs an entry
+func (c *httpClient) log(ctx context.Context, level LogLevel, msg string, fields ...interface{}) {
+	if c.logger == nil {
+		return
+	}
+
+	redacted := make([]interface{}, len(fields))
+	for i, f := range fields {
+		if i%2 == 1 {
+			if key, ok := fields[i-1].(string); ok && isSecretField(key) {
+				redacted[i] = redactedValue
+				continue
+			}
+		}
+		redacted[i] = c.redactValue(f)
+	}
+
+	c.logger.Log(ctx, level, c.redact(msg), redacted...)
+}
+
+// redactedValue replaces the secrets of log entries
+const redactedValue = "[REDACTED]"
+
+var (
+	// auth hashes, API keys and authorization values in JSON bodies and query strings
+	secretPattern = regexp.MustCompile(`(?i)((?:[a-z_]*auth_hash|basic_auth_key|api_key|authorization)"?\s*[:=]\s*"?)[^"&\s,}]+`)
+	// email addresses, including URL encoded ones
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+(?:@|%40)[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// E.164 phone numbers, including URL encoded ones
+	phonePattern = regexp.MustCompile(`(?:\+|%2B)[1-9][0-9]{6,14}`)
+)
+
+// redact removes the secrets and personal data of a string
+func (c *httpClient) redact(s string) string {
+	if c.apiKey != "" {
+		s = strings.Replace(s, c.apiKey, redactedValue, -1)
+	}
+	s = secretPattern.ReplaceAllString(s, "${1}"+redactedValue)
+	s = emailPattern.ReplaceAllString(s, redactedValue)
+	return phonePattern.ReplaceAllString(s, redactedValue)
+}
+
+// redactValue redacts the strings and errors of fields, keeping the other values
+func (c *httpClient) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return c.redact(v)
+	case time.Duration:
+		return v
+	case error:
+		return c.redact(v.Error())
+	case fmt.Stringer:
+		return c.redact(v.String())
+	default:
+		return v
+	}
+}
+
+// isSecretField reports whether the values of a field are secrets
+func isSecretField(key string) bool {
+	key = strings.ToLower(key)
+	return key == "authorization" || key == "api_key" || key == "basic_auth_key" || strings.HasSuffix(key, "auth_hash")
+}
@@ -0,0 +1,178 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type logEntry struct {
+	level  LogLevel
+	msg    string
+	fields []interface{}
+}
+
+// field returns the value of a field of the entry
+func (e logEntry) field(key string) interface{} {
+	for i := 0; i+1 < len(e.fields); i += 2 {
+		if e.fields[i] == key {
+			return e.fields[i+1]
+		}
+	}
+	return nil
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...interface{}) {
+	l.entries = append(l.entries, logEntry{level, msg, fields})
+}
+
+func TestClient_SetStructuredLogger(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	logger := &testLogger{}
+	client.SetStructuredLogger(logger)
+	client.SetIdentityVerification(true)
+
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"success": true, "id": "player-fake-id"}`)
+	})
+	mux.HandleFunc("/players/player-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors": ["No user with this id found"]}`)
+	})
+
+	if _, _, err := client.Players.CreateSMS("+15555550100", PlayerRequest{ExternalUserID: "user-id"}); err != nil {
+		t.Fatalf("CreateSMS returned an error: %v", err)
+	}
+	if _, _, err := client.Players.Get("player-fake-id", PlayerGetOptions{Email: "foo@example.com"}); !IsNotFound(err) {
+		t.Fatalf("Get returned %v, want a not found error", err)
+	}
+
+	secrets := []string{
+		client.apiKey,
+		"+15555550100",
+		"foo@example.com",
+		"foo%40example.com",
+		IdentityHash(client.apiKey, "+15555550100"),
+		IdentityHash(client.apiKey, "user-id"),
+		IdentityHash(client.apiKey, "foo@example.com"),
+	}
+	for _, e := range logger.entries {
+		entry := fmt.Sprint(e.msg, e.fields)
+		for _, secret := range secrets {
+			if strings.Contains(entry, secret) {
+				t.Errorf("log entry %q contains the secret %q", entry, secret)
+			}
+		}
+	}
+
+	var responses, failures int
+	for _, e := range logger.entries {
+		switch e.msg {
+		case "response":
+			responses++
+			if e.field("method") == nil || e.field("path") == nil || e.field("status") == nil || e.field("latency") == nil {
+				t.Errorf("response entry misses fields: %v", e.fields)
+			}
+		case "request failed":
+			failures++
+			if e.level != LogLevelWarn || e.field("status") != http.StatusNotFound {
+				t.Errorf("request failed entry: %+v", e)
+			}
+		}
+	}
+	if responses != 2 || failures != 1 {
+		t.Errorf("logged %d responses and %d failures, want 2 and 1", responses, failures)
+	}
+}
+
+func TestClient_SetLogger(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	var lines []string
+	client.SetLogger(func(args ...interface{}) {
+		lines = append(lines, fmt.Sprint(args...))
+	})
+
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total_count": 0, "offset": 0, "limit": 10, "players": []}`)
+	})
+
+	if _, _, err := client.Players.List(&PlayerListOptions{Limit: 10}); err != nil {
+		t.Fatalf("List returned an error: %v", err)
+	}
+
+	if len(lines) == 0 {
+		t.Fatalf("nothing was logged")
+	}
+	for _, line := range lines {
+		if strings.Contains(line, client.apiKey) {
+			t.Errorf("log line %q contains the API key", line)
+		}
+		if !strings.HasPrefix(line, "[OneSignal] ") {
+			t.Errorf("log line %q should start with [OneSignal]", line)
+		}
+	}
+}
+
+type testSlogLogger struct {
+	levels []string
+}
+
+func (l *testSlogLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	l.levels = append(l.levels, "debug")
+}
+
+func (l *testSlogLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.levels = append(l.levels, "info")
+}
+
+func (l *testSlogLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	l.levels = append(l.levels, "warn")
+}
+
+func (l *testSlogLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.levels = append(l.levels, "error")
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	sl := &testSlogLogger{}
+	logger := NewSlogLogger(sl)
+	for _, level := range []LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError} {
+		logger.Log(context.Background(), level, "msg")
+	}
+
+	if got, want := strings.Join(sl.levels, ","), "debug,info,warn,error"; got != want {
+		t.Errorf("levels: %v, want %v", got, want)
+	}
+}
+
+func TestHTTPClient_redact(t *testing.T) {
+	c := newHTTPClient("secret-api-key")
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Basic secret-api-key", "Basic [REDACTED]"},
+		{`{"identifier_auth_hash":"abc123","language":"en"}`, `{"identifier_auth_hash":"[REDACTED]","language":"en"}`},
+		{"/players/id?app_id=app&email_auth_hash=abc123", "/players/id?app_id=app&email_auth_hash=[REDACTED]"},
+		{`{"identifier":"foo.bar@example.com"}`, `{"identifier":"[REDACTED]"}`},
+		{`{"include_phone_numbers":["+15555550100"]}`, `{"include_phone_numbers":["[REDACTED]"]}`},
+		{"/apps/app-id/users/user-id", "/apps/app-id/users/user-id"},
+	}
+
+	for _, tt := range tests {
+		if got := c.redact(tt.in); got != tt.want {
+			t.Errorf("redact(%q): %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
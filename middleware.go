@@ -0,0 +1,176 @@
+package onesignal
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Operation is the name of an API method, like "notifications.create"
+type Operation string
+
+const (
+	// Requests which don't match a known API method
+	OperationUnknown Operation = ""
+
+	OperationAppsList   Operation = "apps.list"
+	OperationAppsGet    Operation = "apps.get"
+	OperationAppsCreate Operation = "apps.create"
+	OperationAppsUpdate Operation = "apps.update"
+
+	OperationPlayersList       Operation = "players.list"
+	OperationPlayersGet        Operation = "players.get"
+	OperationPlayersCreate     Operation = "players.create"
+	OperationPlayersUpdate     Operation = "players.update"
+	OperationPlayersUpdateTags Operation = "players.update_tags"
+	OperationPlayersDelete     Operation = "players.delete"
+	OperationPlayersCSVExport  Operation = "players.csv_export"
+	OperationPlayersOnSession  Operation = "players.on_session"
+	OperationPlayersOnPurchase Operation = "players.on_purchase"
+	OperationPlayersOnFocus    Operation = "players.on_focus"
+
+	OperationNotificationsList      Operation = "notifications.list"
+	OperationNotificationsGet       Operation = "notifications.get"
+	OperationNotificationsCreate    Operation = "notifications.create"
+	OperationNotificationsCancel    Operation = "notifications.cancel"
+	OperationNotificationsTrackOpen Operation = "notifications.track_open"
+	OperationNotificationsHistory   Operation = "notifications.history"
+
+	OperationSegmentsCreate Operation = "segments.create"
+	OperationSegmentsDelete Operation = "segments.delete"
+
+	OperationOutcomesList Operation = "outcomes.list"
+
+	OperationTemplatesList   Operation = "templates.list"
+	OperationTemplatesGet    Operation = "templates.get"
+	OperationTemplatesCreate Operation = "templates.create"
+	OperationTemplatesUpdate Operation = "templates.update"
+	OperationTemplatesDelete Operation = "templates.delete"
+	OperationTemplatesCopy   Operation = "templates.copy"
+
+	OperationLiveActivitiesSend Operation = "live_activities.send"
+)
+
+// operationRoutes maps the method and path of the API endpoints to their operation.
+// "*" matches any path segment.
+var operationRoutes = []struct {
+	method    string
+	path      string
+	operation Operation
+}{
+	{http.MethodGet, "/apps", OperationAppsList},
+	{http.MethodGet, "/apps/*", OperationAppsGet},
+	{http.MethodPost, "/apps", OperationAppsCreate},
+	{http.MethodPut, "/apps/*", OperationAppsUpdate},
+
+	{http.MethodGet, "/players", OperationPlayersList},
+	{http.MethodGet, "/players/*", OperationPlayersGet},
+	{http.MethodPost, "/players", OperationPlayersCreate},
+	{http.MethodPost, "/players/csv_export", OperationPlayersCSVExport},
+	{http.MethodPut, "/players/*", OperationPlayersUpdate},
+	{http.MethodPut, "/apps/*/users/*", OperationPlayersUpdateTags},
+	{http.MethodDelete, "/players/*", OperationPlayersDelete},
+	{http.MethodPost, "/players/*/on_session", OperationPlayersOnSession},
+	{http.MethodPost, "/players/*/on_purchase", OperationPlayersOnPurchase},
+	{http.MethodPost, "/players/*/on_focus", OperationPlayersOnFocus},
+
+	{http.MethodGet, "/notifications", OperationNotificationsList},
+	{http.MethodGet, "/notifications/*", OperationNotificationsGet},
+	{http.MethodPost, "/notifications", OperationNotificationsCreate},
+	{http.MethodDelete, "/notifications/*", OperationNotificationsCancel},
+	{http.MethodPut, "/notifications/*", OperationNotificationsTrackOpen},
+	{http.MethodPost, "/notifications/*/history", OperationNotificationsHistory},
+
+	{http.MethodPost, "/apps/*/segments", OperationSegmentsCreate},
+	{http.MethodDelete, "/apps/*/segments/*", OperationSegmentsDelete},
+
+	{http.MethodGet, "/apps/*/outcomes", OperationOutcomesList},
+
+	{http.MethodGet, "/templates", OperationTemplatesList},
+	{http.MethodGet, "/templates/*", OperationTemplatesGet},
+	{http.MethodPost, "/templates", OperationTemplatesCreate},
+	{http.MethodPatch, "/templates/*", OperationTemplatesUpdate},
+	{http.MethodDelete, "/templates/*", OperationTemplatesDelete},
+	{http.MethodPost, "/templates/*/copy_to_app", OperationTemplatesCopy},
+
+	{http.MethodPost, "/apps/*/live_activities/*/notifications", OperationLiveActivitiesSend},
+}
+
+// Invoker sends a request with the context ctx and decodes the response into v.
+// op names the operation for the interceptors, it doesn't change the request.
+type Invoker func(ctx context.Context, op Operation, r *http.Request, v interface{}) (*http.Response, error)
+
+// Interceptor wraps the requests sent by Do. ctx is the context of the request r.
+// It can modify the request before calling next, and inspect the decoded response v
+// and the error after. Requests can be sent again by calling next several times.
+type Interceptor func(ctx context.Context, op Operation, r *http.Request, v interface{}, next Invoker) (*http.Response, error)
+
+// Use appends interceptors to the chain of the client.
+// The first interceptor is the outermost one: it sees the request first and the response last.
+func (c *httpClient) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+type operationContextKey struct{}
+
+// WithOperation returns a context setting the operation name of the requests created with it,
+// for requests built with NewRequestWithContext which don't match a known API method.
+func WithOperation(ctx context.Context, op Operation) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, op)
+}
+
+// OperationFromContext returns the operation name set by WithOperation
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	op, ok := ctx.Value(operationContextKey{}).(Operation)
+	return op, ok
+}
+
+// operationOf returns the operation of the request
+func (c *httpClient) operationOf(r *http.Request) Operation {
+	if op, ok := OperationFromContext(r.Context()); ok {
+		return op
+	}
+	return c.routeOf(r)
+}
+
+// routeOf returns the operation of the API endpoint matching the method and path of the request,
+// ignoring the name set by WithOperation
+func (c *httpClient) routeOf(r *http.Request) Operation {
+	path := strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(c.baseURL.Path, "/"))
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, route := range operationRoutes {
+		if route.method != r.Method {
+			continue
+		}
+		if matchSegments(strings.Split(strings.Trim(route.path, "/"), "/"), segments) {
+			return route.operation
+		}
+	}
+	return OperationUnknown
+}
+
+// matchSegments reports whether the path segments match the pattern segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// invoke sends the request through the interceptor chain
+func (c *httpClient) invoke(r *http.Request, v interface{}, last Invoker) (*http.Response, error) {
+	next := last
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func(ctx context.Context, op Operation, r *http.Request, v interface{}) (*http.Response, error) {
+			return interceptor(ctx, op, r, v, inner)
+		}
+	}
+	return next(r.Context(), c.operationOf(r), r, v)
+}
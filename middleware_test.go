@@ -0,0 +1,141 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_Use(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	var calls []string
+	trace := func(name string) Interceptor {
+		return func(ctx context.Context, op Operation, r *http.Request, v interface{}, next Invoker) (*http.Response, error) {
+			calls = append(calls, fmt.Sprintf("%s before %s", name, op))
+			resp, err := next(ctx, op, r, v)
+			calls = append(calls, fmt.Sprintf("%s after %s %v", name, v.(*NotificationCreateResponse).ID, err))
+			return resp, err
+		}
+	}
+	client.Use(trace("a"), trace("b"))
+	client.Use(func(ctx context.Context, op Operation, r *http.Request, v interface{}, next Invoker) (*http.Response, error) {
+		r.Header.Set("Authorization", "Basic other-key")
+		return next(ctx, op, r, v)
+	})
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "Authorization", "Basic other-key")
+		fmt.Fprint(w, `{"id": "notif-id", "recipients": 1}`)
+	})
+
+	if _, _, err := client.Notifications.Create(sampleNotificationRequest); err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	want := []string{
+		"a before notifications.create",
+		"b before notifications.create",
+		"b after notif-id <nil>",
+		"a after notif-id <nil>",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls: %v, want %v", calls, want)
+	}
+}
+
+func TestClient_Use_retry(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.Use(func(ctx context.Context, op Operation, r *http.Request, v interface{}, next Invoker) (*http.Response, error) {
+		resp, err := next(ctx, op, r, v)
+		if IsStatus(err, http.StatusServiceUnavailable) {
+			return next(ctx, op, r, v)
+		}
+		return resp, err
+	})
+
+	attempts := 0
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		testBody(t, r, &PlayerRequest{}, &PlayerRequest{AppID: "fake-app-id", Identifier: "token"})
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"success": true, "id": "player-id"}`)
+	})
+
+	res, _, err := client.Players.Create(PlayerRequest{AppID: "fake-app-id", Identifier: "token"})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if res.ID != "player-id" || attempts != 2 {
+		t.Errorf("Create returned %+v after %d attempts", res, attempts)
+	}
+}
+
+func TestClient_operationOf(t *testing.T) {
+	client, _ := NewClient("app-id", "api-key")
+
+	tests := []struct {
+		method string
+		path   string
+		ctx    context.Context
+		want   Operation
+	}{
+		{"GET", "/players?app_id=app-id", nil, OperationPlayersList},
+		{"POST", "/players/csv_export?app_id=app-id", nil, OperationPlayersCSVExport},
+		{"PUT", "/players/player-id", nil, OperationPlayersUpdate},
+		{"POST", "/players/player-id/on_focus", nil, OperationPlayersOnFocus},
+		{"PUT", "/apps/app-id/users/user-id", nil, OperationPlayersUpdateTags},
+		{"DELETE", "/notifications/notif-id?app_id=app-id", nil, OperationNotificationsCancel},
+		{"DELETE", "/apps/app-id/segments/segment-id", nil, OperationSegmentsDelete},
+		{"POST", "/apps/app-id/live_activities/activity-id/notifications", nil, OperationLiveActivitiesSend},
+		{"GET", "/unknown", nil, OperationUnknown},
+		{"GET", "/unknown", WithOperation(context.Background(), "custom.get"), "custom.get"},
+	}
+
+	for _, tt := range tests {
+		ctx := tt.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		req, err := client.NewRequestWithContext(ctx, tt.method, tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := client.operationOf(req); got != tt.want {
+			t.Errorf("operationOf(%s %s): %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestClient_Use_context(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.Use(func(ctx context.Context, op Operation, r *http.Request, v interface{}, next Invoker) (*http.Response, error) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		return next(ctx, op, r, v)
+	})
+
+	mux.HandleFunc("/players/player-id", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+		fmt.Fprint(w, `{"id": "player-id"}`)
+	})
+
+	if _, _, err := client.Players.Get("player-id"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get returned %v, want the deadline of the interceptor to be exceeded", err)
+	}
+}
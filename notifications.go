@@ -1,10 +1,14 @@
 package onesignal
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 type MessageType string
@@ -131,11 +135,7 @@ type Notification struct {
 		SMS                *DeliveryStats `json:"sms,omitempty"`
 		EdgeWebPush        *DeliveryStats `json:"edge_web_push,omitempty"`
 	} `json:"platform_delivery_stats"`
-	Outcomes []struct {
-		ID          string `json:"id"`
-		Value       int64  `json:"value"`
-		Aggregation string `json:"aggregation"`
-	} `json:"outcomes,omitempty"`
+	Outcomes []Outcome `json:"outcomes,omitempty"`
 }
 
 // NotificationRequest represents a request to create a notification.
@@ -194,7 +194,8 @@ type NotificationRequest struct {
 	IncludeChromeRegIDs       []string    `json:"include_chrome_reg_ids,omitempty"`
 	IncludeChromeWebRegIDs    []string    `json:"include_chrome_web_reg_ids,omitempty"`
 	AppIDs                    []string    `json:"app_ids,omitempty"`
-	Tags                      interface{} `json:"tags,omitempty"`
+	// Legacy tag targeting. Prefer Filters with FilterTag conditions.
+	Tags interface{} `json:"tags,omitempty"`
 
 	// Describes whether to set or increase/decrease your app's iOS badge count by the ios_badgeCount specified count.
 	// Can specify None, SetTo, or Increase.
@@ -355,6 +356,9 @@ type NotificationRequest struct {
 	// Use a template you setup on our dashboard.
 	// The template_id is the UUID found in the URL when viewing a template on our dashboard.
 	TemplateID string `json:"template_id,omitempty"`
+	// Data substituted in the template as {{ message.custom_data.<key> }}.
+	// https://documentation.onesignal.com/docs/using-api-custom-data-in-templates
+	CustomData map[string]interface{} `json:"custom_data,omitempty"`
 	// Android: Notifications with the same group will be stacked together using Android's Notification Grouping feature.
 	AndroidGroup string `json:"android_group,omitempty"`
 	// Android: Summary message to display when 2+ notifications are stacked together. Default is "# new messages".
@@ -381,8 +385,13 @@ type NotificationRequest struct {
 	// iOS 15+ Focus Modes and Interruption Levels indicate the priority and delivery timing of a notification, to ‘interrupt’ the user.
 	IOSInterruptionLevel IOSInterruptionLevel `json:"ios_interruption_level,omitempty"`
 
-	Filters    interface{} `json:"filters,omitempty"`
-	ExternalID string      `json:"external_id,omitempty"`
+	// Target users based on filters. Use the Filters type to build them.
+	// https://documentation.onesignal.com/reference/create-notification#send-to-users-based-on-filters
+	Filters interface{} `json:"filters,omitempty"`
+	// Correlation and idempotency key. A request received with this parameter will first look for
+	// another notification with the same external_id. If one exists, a notification will not be sent,
+	// so the create request can be retried safely.
+	ExternalID string `json:"external_id,omitempty"`
 	// Use to target a specific experience in your App Clip, or to target your notification to a specific window in a multi-scene App.
 	// https://documentation.onesignal.com/docs/app-clip-support
 	TargetContentIdentifier string `json:"target_content_identifier,omitempty"`
@@ -424,9 +433,107 @@ type NotificationRequest struct {
 // NotificationCreateResponse wraps the standard http.Response for the
 // NotificationsService.Create method
 type NotificationCreateResponse struct {
-	ID         string      `json:"id"`
-	Recipients int         `json:"recipients"`
-	Errors     interface{} `json:"errors"`
+	ID         string `json:"id"`
+	Recipients int    `json:"recipients"`
+	// Errors is nil unless OneSignal reported invalid or unsubscribed recipients.
+	Errors *NotificationErrors `json:"errors,omitempty"`
+}
+
+// NotificationErrors represents the errors reported by OneSignal about the recipients of a notification.
+// The API returns either a list of messages or an object listing invalid recipients, for example
+// {"invalid_player_ids": ["..."], "invalid_external_user_ids": ["..."]}.
+type NotificationErrors struct {
+	// Error messages, when the errors are returned as a list
+	Messages []string `json:"-"`
+	// Player IDs that don't exist or were unsubscribed
+	InvalidPlayerIDs []string `json:"invalid_player_ids,omitempty"`
+	// External user IDs that aren't associated to any subscribed device
+	InvalidExternalUserIDs []string `json:"invalid_external_user_ids,omitempty"`
+	// Phone numbers that aren't valid or subscribed
+	InvalidPhoneNumbers []string `json:"invalid_phone_numbers,omitempty"`
+	// Email addresses that aren't valid or subscribed
+	InvalidEmailTokens []string `json:"invalid_email_tokens,omitempty"`
+	// Set when none of the targeted players are subscribed
+	AllPlayersNotSubscribed bool `json:"-"`
+}
+
+// notificationErrorsAllNotSubscribed is the message returned when no targeted player can receive the notification
+const notificationErrorsAllNotSubscribed = "All included players are not subscribed"
+
+// HasInvalidRecipients reports whether some recipients should be removed from future notifications
+func (e *NotificationErrors) HasInvalidRecipients() bool {
+	return len(e.InvalidPlayerIDs) > 0 ||
+		len(e.InvalidExternalUserIDs) > 0 ||
+		len(e.InvalidPhoneNumbers) > 0 ||
+		len(e.InvalidEmailTokens) > 0
+}
+
+// UnmarshalJSON decodes both the list and the object form of the errors
+func (e *NotificationErrors) UnmarshalJSON(data []byte) error {
+	*e = NotificationErrors{}
+
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case bytes.HasPrefix(data, []byte("[")):
+		if err := json.Unmarshal(data, &e.Messages); err != nil {
+			return err
+		}
+	case bytes.HasPrefix(data, []byte(`"`)):
+		var msg string
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		e.Messages = []string{msg}
+	default:
+		type alias NotificationErrors
+		if err := json.Unmarshal(data, (*alias)(e)); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range e.Messages {
+		if m == notificationErrorsAllNotSubscribed {
+			e.AllPlayersNotSubscribed = true
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the errors in the form they are returned by OneSignal
+func (e NotificationErrors) MarshalJSON() ([]byte, error) {
+	if !e.HasInvalidRecipients() {
+		messages := e.Messages
+		if messages == nil {
+			messages = []string{}
+		}
+		return json.Marshal(messages)
+	}
+
+	type alias NotificationErrors
+	return json.Marshal(alias(e))
+}
+
+// strings returns human readable descriptions of the errors
+func (e *NotificationErrors) strings() []string {
+	result := append([]string{}, e.Messages...)
+	for _, invalid := range []struct {
+		name   string
+		values []string
+	}{
+		{"invalid player ids", e.InvalidPlayerIDs},
+		{"invalid external user ids", e.InvalidExternalUserIDs},
+		{"invalid phone numbers", e.InvalidPhoneNumbers},
+		{"invalid email tokens", e.InvalidEmailTokens},
+	} {
+		if len(invalid.values) > 0 {
+			result = append(result, fmt.Sprintf("%s: %s", invalid.name, strings.Join(invalid.values, ", ")))
+		}
+	}
+
+	return result
 }
 
 // NotificationListOptions specifies the parameters to the
@@ -444,7 +551,8 @@ type NotificationListOptions struct {
 // NotificationGetOptions specifies the parameters to the
 // NotificationsService.Get method
 type NotificationGetOptions struct {
-	// Comma-separated list of names and the value (sum/count) for the returned outcome data.
+	// Names and the value (sum/count) for the returned outcome data, like "os__click.count".
+	// See OutcomeName to format them.
 	OutcomeNames []string `json:"outcome_names"`
 	// Time range for the returned data.
 	// The values can be 1h (for the last 1 hour data), 1d (for the last 1 day data), or 1mo (for the last 1 month data).
@@ -475,6 +583,11 @@ type NotificationDeleteOptions struct {
 // OneSignal API docs:
 // https://documentation.onesignal.com/reference/view-notifications
 func (s *NotificationsService) List(opt ...NotificationListOptions) (*NotificationListResponse, *http.Response, error) {
+	return s.ListContext(context.Background(), opt...)
+}
+
+// ListContext lists the notifications with the provided context.
+func (s *NotificationsService) ListContext(ctx context.Context, opt ...NotificationListOptions) (*NotificationListResponse, *http.Response, error) {
 	// build the URL with the query string
 	u, err := url.Parse("/notifications")
 	if err != nil {
@@ -492,7 +605,7 @@ func (s *NotificationsService) List(opt ...NotificationListOptions) (*Notificati
 	u.RawQuery = q.Encode()
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -511,6 +624,11 @@ func (s *NotificationsService) List(opt ...NotificationListOptions) (*Notificati
 // OneSignal API docs:
 // https://documentation.onesignal.com/reference/view-notification
 func (s *NotificationsService) Get(notificationID string, opt ...NotificationGetOptions) (*Notification, *http.Response, error) {
+	return s.GetContext(context.Background(), notificationID, opt...)
+}
+
+// GetContext gets a single notification with the provided context.
+func (s *NotificationsService) GetContext(ctx context.Context, notificationID string, opt ...NotificationGetOptions) (*Notification, *http.Response, error) {
 	// build the URL with the query string
 	u, err := url.Parse("/notifications/" + notificationID)
 	if err != nil {
@@ -519,23 +637,12 @@ func (s *NotificationsService) Get(notificationID string, opt ...NotificationGet
 	q := u.Query()
 	q.Set("app_id", s.client.appID)
 	if len(opt) > 0 {
-		if opt[0].OutcomeAttribution != "" {
-			q.Set("outcome_attribution", opt[0].OutcomeAttribution)
-		}
-		if opt[0].OutcomePlatforms != "" {
-			q.Set("outcome_platforms", opt[0].OutcomePlatforms)
-		}
-		if opt[0].OutcomeTimeRange != "" {
-			q.Set("outcome_time_range", opt[0].OutcomeTimeRange)
-		}
-		for _, n := range opt[0].OutcomeNames {
-			q.Set("outcome_names", n)
-		}
+		setOutcomeQuery(q, opt[0].OutcomeNames, opt[0].OutcomeTimeRange, opt[0].OutcomePlatforms, opt[0].OutcomeAttribution)
 	}
 	u.RawQuery = q.Encode()
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -554,18 +661,32 @@ func (s *NotificationsService) Get(notificationID string, opt ...NotificationGet
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/notifications-create-notification
 func (s *NotificationsService) Create(opt *NotificationRequest) (*NotificationCreateResponse, *http.Response, error) {
+	return s.CreateContext(context.Background(), opt)
+}
+
+// CreateContext creates a notification with the provided context.
+func (s *NotificationsService) CreateContext(ctx context.Context, opt *NotificationRequest) (*NotificationCreateResponse, *http.Response, error) {
 	// build the URL
 	u, err := url.Parse("/notifications")
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if s.client.validateRequests {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// create the request
 	opt.AppID = s.client.appID
-	req, err := s.client.NewRequest("POST", u.String(), opt)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
+	if opt.ExternalID != "" {
+		req.Header.Set(IdempotencyKeyHeader, opt.ExternalID)
+	}
 
 	createRes := &NotificationCreateResponse{}
 	resp, err := s.client.Do(req, createRes)
@@ -581,6 +702,11 @@ func (s *NotificationsService) Create(opt *NotificationRequest) (*NotificationCr
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/notificationsid-cancel-notification
 func (s *NotificationsService) Delete(notificationID string) (*SuccessResponse, *http.Response, error) {
+	return s.DeleteContext(context.Background(), notificationID)
+}
+
+// DeleteContext deletes a notification with the provided context.
+func (s *NotificationsService) DeleteContext(ctx context.Context, notificationID string) (*SuccessResponse, *http.Response, error) {
 	// build the URL
 	u, err := url.Parse(fmt.Sprintf("/notifications/%s?app_id=%s", notificationID, s.client.appID))
 	if err != nil {
@@ -588,7 +714,7 @@ func (s *NotificationsService) Delete(notificationID string) (*SuccessResponse,
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("DELETE", u.String(), nil)
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", u.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -601,3 +727,44 @@ func (s *NotificationsService) Delete(notificationID string) (*SuccessResponse,
 
 	return deleteRes, resp, err
 }
+
+// notificationTrackOpenRequest is the body of the NotificationsService.TrackOpen method
+type notificationTrackOpenRequest struct {
+	AppID    string `json:"app_id"`
+	Opened   bool   `json:"opened"`
+	PlayerID string `json:"player_id"`
+}
+
+// TrackOpen reports that a player opened a notification.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/track-open
+func (s *NotificationsService) TrackOpen(notificationID, playerID string) (*SuccessResponse, *http.Response, error) {
+	return s.TrackOpenContext(context.Background(), notificationID, playerID)
+}
+
+// TrackOpenContext reports that a player opened a notification with the provided context.
+func (s *NotificationsService) TrackOpenContext(ctx context.Context, notificationID, playerID string) (*SuccessResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse("/notifications/" + notificationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", u.String(), &notificationTrackOpenRequest{
+		AppID:    s.client.appID,
+		Opened:   true,
+		PlayerID: playerID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trackRes := &SuccessResponse{}
+	resp, err := s.client.Do(req, trackRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return trackRes, resp, err
+}
@@ -1,12 +1,16 @@
 package onesignal
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/hgiasac/onesignal/testhelper"
 )
@@ -227,9 +231,9 @@ func TestNotificationsService_Create_returnsError(t *testing.T) {
 	})
 
 	_, resp, err := client.Notifications.Create(notificationRequest)
-	errResp, ok := err.(*ErrorResponse)
-	if !ok {
-		t.Errorf("Error should be of type ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("Error should wrap an ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
 	}
 
 	want := "Notification content must not be null for any languages."
@@ -265,15 +269,13 @@ func TestNotificationsService_Create_invalidPlayerIds(t *testing.T) {
 		t.Errorf("Create returned an error: %v", err)
 	}
 
-	ids := []interface{}{
-		"5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986",
-		"00cb73f8-5815-11e5-ba69-f75522da5528",
-	}
-	errors := map[string]interface{}{
-		"invalid_player_ids": ids,
-	}
 	want := &NotificationCreateResponse{
-		Errors: errors,
+		Errors: &NotificationErrors{
+			InvalidPlayerIDs: []string{
+				"5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986",
+				"00cb73f8-5815-11e5-ba69-f75522da5528",
+			},
+		},
 	}
 	if !reflect.DeepEqual(createResp, want) {
 		t.Errorf("Errors: %v, want %v", createResp, want)
@@ -302,19 +304,106 @@ func TestNotificationsService_Create_noSubscribedPlayers(t *testing.T) {
 		t.Errorf("Create returned an error: %v", err)
 	}
 
-	errors := []interface{}{
-		"All included players are not subscribed",
-	}
 	want := &NotificationCreateResponse{
 		ID:         "",
 		Recipients: 0,
-		Errors:     errors,
+		Errors: &NotificationErrors{
+			Messages:                []string{"All included players are not subscribed"},
+			AllPlayersNotSubscribed: true,
+		},
 	}
 	if !reflect.DeepEqual(createResp, want) {
 		t.Errorf("Errors: %v, want %v", createResp, want)
 	}
 }
 
+func TestNotificationsService_Create_invalidRecipients(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"id": "notif-fake-id",
+			"recipients": 1,
+			"errors": {
+				"invalid_external_user_ids": ["user-1"],
+				"invalid_phone_numbers": ["+15555550100"],
+				"invalid_email_tokens": ["foo@example.com"]
+			}
+		}`)
+	})
+
+	createResp, _, err := client.Notifications.Create(sampleNotificationRequest)
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	want := &NotificationErrors{
+		InvalidExternalUserIDs: []string{"user-1"},
+		InvalidPhoneNumbers:    []string{"+15555550100"},
+		InvalidEmailTokens:     []string{"foo@example.com"},
+	}
+	if !reflect.DeepEqual(createResp.Errors, want) {
+		t.Errorf("Errors: %+v, want %+v", createResp.Errors, want)
+	}
+
+	if !createResp.Errors.HasInvalidRecipients() {
+		t.Errorf("HasInvalidRecipients should be true")
+	}
+}
+
+func TestNotificationsService_Create_invalidPlayerIdsError(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{
+			"errors": {
+				"invalid_player_ids": ["5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986"]
+			}
+		}`)
+	})
+
+	_, _, err := client.Notifications.Create(sampleNotificationRequest)
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("Error should wrap an ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
+	}
+
+	if got, want := errResp.Details.InvalidPlayerIDs, []string{"5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("InvalidPlayerIDs: %v, want %v", got, want)
+	}
+
+	if got, want := errResp.Messages, []string{"invalid player ids: 5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Messages: %v, want %v", got, want)
+	}
+
+	if !IsInvalidPlayerIDs(err) {
+		t.Errorf("IsInvalidPlayerIDs should be true")
+	}
+}
+
+func TestNotificationErrors_MarshalJSON(t *testing.T) {
+	for _, tc := range []struct {
+		errors NotificationErrors
+		want   string
+	}{
+		{NotificationErrors{}, `[]`},
+		{NotificationErrors{Messages: []string{"foo"}}, `["foo"]`},
+		{NotificationErrors{InvalidPlayerIDs: []string{"id1"}}, `{"invalid_player_ids":["id1"]}`},
+	} {
+		b, err := json.Marshal(tc.errors)
+		if err != nil {
+			t.Fatalf("MarshalJSON returned an error: %v", err)
+		}
+		if string(b) != tc.want {
+			t.Errorf("MarshalJSON: %s, want %s", b, tc.want)
+		}
+	}
+}
+
 func TestNotificationsService_Delete(t *testing.T) {
 	server, mux, client := setup(t)
 	defer teardown(server)
@@ -350,3 +439,62 @@ func TestNotificationsService_Delete(t *testing.T) {
 		t.Errorf("Request has not been sent")
 	}
 }
+
+func TestNotificationsService_CreateContext_deadlineExceeded(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err := client.Notifications.CreateContext(ctx, sampleNotificationRequest)
+	if err == nil {
+		t.Fatalf("CreateContext should return an error")
+	}
+
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("Context error is %v, want %v", ctx.Err(), context.DeadlineExceeded)
+	}
+}
+
+func TestNotificationsService_TrackOpen(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	requestSent := false
+
+	mux.HandleFunc("/notifications/notif-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "PUT")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+		testBody(t, r, &notificationTrackOpenRequest{}, &notificationTrackOpenRequest{
+			AppID:    client.appID,
+			Opened:   true,
+			PlayerID: "player-fake-id",
+		})
+
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	trackRes, _, err := client.Notifications.TrackOpen("notif-fake-id", "player-fake-id")
+	if err != nil {
+		t.Errorf("TrackOpen returned an error: %v", err)
+	}
+
+	want := &SuccessResponse{Success: true}
+	if !reflect.DeepEqual(trackRes, want) {
+		t.Errorf("TrackOpen returned %+v, want %+v", trackRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
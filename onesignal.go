@@ -2,10 +2,12 @@ package onesignal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
@@ -18,10 +20,16 @@ const (
 // Client manages communication with the OneSignal application API.
 type Client struct {
 	*httpClient
-	appID string
-
-	Players       *PlayersService
-	Notifications *NotificationsService
+	appID                string
+	validateRequests     bool
+	identityVerification bool
+
+	Players        *PlayersService
+	Notifications  *NotificationsService
+	Segments       *SegmentsService
+	Outcomes       *OutcomesService
+	Templates      *TemplatesService
+	LiveActivities *LiveActivitiesService
 }
 
 // UserClient manages OneSignal applications.
@@ -56,6 +64,22 @@ type SuccessResponse struct {
 // ErrorResponse reports one or more errors caused by an API request.
 type ErrorResponse struct {
 	Messages []string `json:"errors"`
+	// Typed errors, including the invalid recipients when errors are returned as an object
+	Details NotificationErrors `json:"-"`
+}
+
+// UnmarshalJSON decodes both the list and the object form of the errors
+func (e *ErrorResponse) UnmarshalJSON(data []byte) error {
+	var body struct {
+		Errors NotificationErrors `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+
+	e.Details = body.Errors
+	e.Messages = body.Errors.strings()
+	return nil
 }
 
 func (e *ErrorResponse) Error() string {
@@ -77,9 +101,14 @@ func NewClient(appID string, apiKey string) (*Client, error) {
 		appID:      appID,
 		httpClient: newHTTPClient(apiKey),
 	}
+	c.attributes = []Attribute{{AttributeAppID, appID}}
 
 	c.Players = &PlayersService{client: c}
 	c.Notifications = &NotificationsService{client: c}
+	c.Segments = &SegmentsService{client: c}
+	c.Outcomes = &OutcomesService{client: c}
+	c.Templates = &TemplatesService{client: c}
+	c.LiveActivities = &LiveActivitiesService{client: c}
 
 	return c, nil
 }
@@ -93,15 +122,25 @@ type httpClient struct {
 	baseURL *url.URL
 	apiKey  string
 	client  *http.Client
-	logger  func(...interface{})
+	logger  Logger
+
+	retryPolicy *RetryPolicy
+	rateLimiter RateLimiter
+
+	interceptors []Interceptor
+
+	instrumentation Instrumentation
+	// common attributes of the spans and metrics
+	attributes []Attribute
 }
 
 func newHTTPClient(apiKey string) *httpClient {
 	baseURL, _ := url.Parse(defaultBaseURL)
 	return &httpClient{
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		client:  http.DefaultClient,
+		apiKey:          apiKey,
+		baseURL:         baseURL,
+		client:          http.DefaultClient,
+		instrumentation: Instrumentation{Tracer: noopTracer{}, Meter: noopMeter{}},
 	}
 }
 
@@ -121,9 +160,14 @@ func (c *httpClient) SetHTTPClient(client *http.Client) {
 	c.client = client
 }
 
-// SetHTTPClient set custom debug logger
+// SetLogger set custom debug logger, receiving formatted and redacted entries.
+// See SetStructuredLogger for structured logging.
 func (c *httpClient) SetLogger(logger func(args ...interface{})) {
-	c.logger = logger
+	if logger == nil {
+		c.logger = nil
+		return
+	}
+	c.logger = funcLogger(logger)
 }
 
 // NewRequest creates an API request.
@@ -132,14 +176,20 @@ func (c *httpClient) SetLogger(logger func(args ...interface{})) {
 // The AuthKeyType will determine which authorization token (APP or USER) is
 // used for the request.
 func (c *httpClient) NewRequest(method, path string, body interface{}) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, path, body)
+}
+
+// NewRequestWithContext creates an API request with the provided context.
+// The context controls the entire lifetime of the request and its response,
+// so cancellation and deadlines are honored by Do.
+func (c *httpClient) NewRequestWithContext(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
 	u, err := url.Parse(c.baseURL.String() + path)
 	if err != nil {
 		return nil, err
 	}
 
-	c.printDebug(fmt.Sprintf("[OneSignal] requesting url: %s", u.String()))
-
 	var buf io.ReadWriter
+	var bodyJSON string
 	if body != nil {
 		b := new(bytes.Buffer)
 		err := json.NewEncoder(b).Encode(body)
@@ -147,13 +197,10 @@ func (c *httpClient) NewRequest(method, path string, body interface{}) (*http.Re
 			return nil, err
 		}
 		buf = b
-
-		if c.logger != nil {
-			c.logger("[OneSignal] request body: " + b.String())
-		}
+		bodyJSON = strings.TrimSpace(b.String())
 	}
 
-	req, err := http.NewRequest(method, u.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
 	if err != nil {
 		return nil, err
 	}
@@ -161,10 +208,15 @@ func (c *httpClient) NewRequest(method, path string, body interface{}) (*http.Re
 	// set header and access token
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Basic %s", c.apiKey))
 
-	token := fmt.Sprintf("Basic %s", c.apiKey)
-	c.printDebug("[OneSignal] Authorization:", token)
-	req.Header.Add("Authorization", token)
+	if c.logger != nil {
+		fields := []interface{}{"method", method, "url", u.String()}
+		if bodyJSON != "" {
+			fields = append(fields, "body", bodyJSON)
+		}
+		c.log(ctx, LogLevelDebug, "request", fields...)
+	}
 
 	return req, nil
 }
@@ -172,9 +224,34 @@ func (c *httpClient) NewRequest(method, path string, body interface{}) (*http.Re
 // Sends an API request and returns the API response.
 // Return JSON decoded and stored in the value pointed to by v,
 // or an error if an API error has occurred.
+// The request is canceled when the context of r is done,
+// and transient failures are retried according to the retry policy.
+// Requests go through the interceptors added with Use, and are traced and measured
+// by the instrumentation of the client.
 func (c *httpClient) Do(r *http.Request, v interface{}) (*http.Response, error) {
+	return c.instrument(r, v, func(r *http.Request) (*http.Response, error) {
+		return c.invoke(r, v, c.do)
+	})
+}
+
+// do sends the request and decodes the response, at the end of the interceptor chain
+// The context passed by the interceptors replaces the context of the request.
+func (c *httpClient) do(ctx context.Context, _ Operation, r *http.Request, v interface{}) (*http.Response, error) {
+	if ctx != r.Context() {
+		r = r.WithContext(ctx)
+	}
+
+	// rewind the body, interceptors may send the request several times
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+
 	// send the request
-	resp, err := c.client.Do(r)
+	resp, err := c.sendWithRetry(r)
 	if err != nil {
 		return nil, err
 	}
@@ -182,13 +259,14 @@ func (c *httpClient) Do(r *http.Request, v interface{}) (*http.Response, error)
 
 	err = checkErrorResponse(resp)
 	if err != nil {
+		c.log(r.Context(), LogLevelWarn, "request failed", "method", r.Method, "path", r.URL.Path, "status", resp.StatusCode, "error", err)
 		return resp, err
 	}
 
 	if c.logger != nil {
 		var b bytes.Buffer
 		b.ReadFrom(resp.Body)
-		c.printDebug("response body: ", b.String())
+		c.log(r.Context(), LogLevelDebug, "response body", "method", r.Method, "path", r.URL.Path, "body", strings.TrimSpace(b.String()))
 		err = json.Unmarshal(b.Bytes(), &v)
 	} else {
 		dec := json.NewDecoder(resp.Body)
@@ -202,27 +280,21 @@ func (c *httpClient) Do(r *http.Request, v interface{}) (*http.Response, error)
 	return resp, nil
 }
 
-func (c *httpClient) printDebug(args ...interface{}) {
-	if c.logger != nil {
-		c.logger(args...)
-	}
-}
-
 // checkErrorResponse checks the API response for errors, by http status code
-// and returns them if present
+// and returns them as an *APIError if present
 func checkErrorResponse(r *http.Response) error {
 	switch r.StatusCode {
 	case http.StatusOK, http.StatusNoContent:
 		return nil
-	case http.StatusInternalServerError:
-		return errors.New("internal server error")
 	default:
-		errResp := new(ErrorResponse)
-		dec := json.NewDecoder(r.Body)
-		err := dec.Decode(&errResp)
-		if err != nil {
-			return fmt.Errorf("couldn't decode response body JSON: %v", err)
+		var body []byte
+		if r.Body != nil {
+			b, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return fmt.Errorf("couldn't read error response body: %v", err)
+			}
+			body = b
 		}
-		return errResp
+		return newAPIError(r, body)
 	}
 }
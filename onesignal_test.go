@@ -1,7 +1,9 @@
 package onesignal
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -154,6 +156,21 @@ func TestNewRequest_emptyBody(t *testing.T) {
 	}
 }
 
+func TestNewRequestWithContext(t *testing.T) {
+	c := setupClient(t)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	req, err := c.NewRequestWithContext(ctx, "GET", "/", nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext returned unexpected error: %v", err)
+	}
+
+	if got := req.Context().Value(ctxKey{}); got != "value" {
+		t.Errorf("NewRequestWithContext context value is %v, want %v", got, "value")
+	}
+}
+
 func TestDo(t *testing.T) {
 	server, mux, client := setup(t)
 	defer teardown(server)
@@ -177,6 +194,30 @@ func TestDo(t *testing.T) {
 	}
 }
 
+func TestDo_contextCanceled(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	requestSent := false
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+		fmt.Fprint(w, `{}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := client.NewRequestWithContext(ctx, "GET", "/", nil)
+	_, err := client.Do(req, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Do error is %v, want %v", err, context.Canceled)
+	}
+
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
+
 func TestDo_httpError(t *testing.T) {
 	server, mux, client := setup(t)
 	defer teardown(server)
@@ -188,9 +229,26 @@ func TestDo_httpError(t *testing.T) {
 	req, _ := client.NewRequest("GET", "/", nil)
 	_, err := client.Do(req, nil)
 
-	_, ok := err.(*ErrorResponse)
-	if ok {
-		t.Errorf("Error should be `couldn't decode response body JSON` but got %v: %+v", reflect.TypeOf(err), err)
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("Error should be of type APIError but got %v: %+v", reflect.TypeOf(err), err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Status code: %d, want %d", apiErr.StatusCode, http.StatusBadRequest)
+	}
+
+	if apiErr.Method != "GET" || apiErr.URL != server.URL+"/" {
+		t.Errorf("Request: %s %s, want %s %s", apiErr.Method, apiErr.URL, "GET", server.URL+"/")
+	}
+
+	if got, want := string(apiErr.Body), "Bad Request\n"; got != want {
+		t.Errorf("Body: %q, want %q", got, want)
+	}
+
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		t.Errorf("Error shouldn't wrap an ErrorResponse when the body isn't JSON")
 	}
 }
 
@@ -216,13 +274,9 @@ func TestCheckResponse_badRequest(t *testing.T) {
 		}`)),
 	}
 
-	err, ok := checkErrorResponse(r).(*ErrorResponse)
-	if !ok {
-		t.Errorf("checkErrorResponse return value should be of type ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
-	}
-
-	if err == nil {
-		t.Fatalf("checkErrorResponse should return an error")
+	var err *ErrorResponse
+	if e := checkErrorResponse(r); !errors.As(e, &err) {
+		t.Fatalf("checkErrorResponse return value should wrap an ErrorResponse but is %v: %+v", reflect.TypeOf(e), e)
 	}
 
 	if len(err.Messages) == 0 {
@@ -246,8 +300,23 @@ func TestCheckResponse_noBody(t *testing.T) {
 		t.Fatalf("checkErrorResponse should return an error")
 	}
 
-	if err.Error() != "couldn't decode response body JSON: EOF" {
-		t.Errorf("checkErrorResponse return value should be of type ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("checkErrorResponse return value should be of type APIError but is %v: %+v", reflect.TypeOf(err), err)
+	}
+
+	if apiErr.Response != nil || len(apiErr.Messages) > 0 {
+		t.Errorf("APIError shouldn't contain a parsed response: %+v", apiErr)
 	}
+}
 
+func TestCheckResponse_internalServerError(t *testing.T) {
+	r := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}
+
+	if got := checkErrorResponse(r); !IsStatus(got, http.StatusInternalServerError) {
+		t.Errorf("checkErrorResponse returned %+v, want status %d", got, http.StatusInternalServerError)
+	}
 }
@@ -0,0 +1,107 @@
+package onesignaltest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hgiasac/onesignal"
+)
+
+// routeApps handles the /apps endpoints, authenticated by the user auth key
+func (s *Server) routeApps(w http.ResponseWriter, r *http.Request, parts []string) {
+	if !s.authorizeUser(w, r) {
+		return
+	}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		apps := []onesignal.App{}
+		for _, a := range s.apps {
+			apps = append(apps, a.stats())
+		}
+		writeJSON(w, http.StatusOK, apps)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		s.createApp(w, r)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		a := s.appByID(parts[0])
+		if a == nil {
+			writeErrors(w, http.StatusNotFound, "Couldn't find app with id = "+parts[0])
+			return
+		}
+		writeJSON(w, http.StatusOK, a.stats())
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		s.updateApp(w, r, parts[0])
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request) {
+	var req onesignal.AppRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeErrors(w, http.StatusBadRequest, "Name can't be blank")
+		return
+	}
+
+	now := s.now().UTC()
+	a := &app{App: onesignal.App{
+		ID:           newID(),
+		BasicAuthKey: newID(),
+		CreatedAt:    now,
+	}}
+	a.apply(req, now)
+	s.apps = append(s.apps, a)
+
+	writeJSON(w, http.StatusOK, a.stats())
+}
+
+func (s *Server) updateApp(w http.ResponseWriter, r *http.Request, id string) {
+	var req onesignal.AppRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	a := s.appByID(id)
+	if a == nil {
+		writeErrors(w, http.StatusNotFound, "Couldn't find app with id = "+id)
+		return
+	}
+
+	a.apply(req, s.now().UTC())
+	writeJSON(w, http.StatusOK, a.stats())
+}
+
+// apply sets the fields of the request on the app
+func (a *app) apply(req onesignal.AppRequest, now time.Time) {
+	setString(&a.Name, req.Name)
+	if req.APNSEnv != "" {
+		a.APNSEnv = req.APNSEnv
+	}
+	setString(&a.GCMKey, req.GCMKey)
+	setString(&a.ChromeWebOrigin, req.ChromeWebOrigin)
+	setString(&a.ChromeWebDefaultNotificationIcon, req.ChromeWebDefaultNotificationIcon)
+	setString(&a.ChromeWebSubDomain, req.ChromeWebSubDomain)
+	setString(&a.SiteName, req.SiteName)
+	setString(&a.SafariSiteOrigin, req.SafariSiteOrigin)
+	setString(&a.SafariIcon16x16, req.SafariIcon16x16)
+	setString(&a.SafariIcon32x32, req.SafariIcon32x32)
+	setString(&a.SafariIcon64x64, req.SafariIcon64x64)
+	setString(&a.SafariIcon128x128, req.SafariIcon128x128)
+	setString(&a.SafariIcon256x256, req.SafariIcon256x256)
+	a.UpdatedAt = now
+}
+
+// stats returns the app with its player counts
+func (a *app) stats() onesignal.App {
+	res := a.App
+	res.Players = len(a.players)
+	res.MessagablePlayers = 0
+	for _, p := range a.players {
+		if subscribed(p) {
+			res.MessagablePlayers++
+		}
+	}
+	return res
+}
@@ -0,0 +1,341 @@
+package onesignaltest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Redacted replaces the secrets of the recorded interactions
+const Redacted = "[REDACTED]"
+
+// RecorderMode is the mode of a Recorder
+type RecorderMode int
+
+const (
+	// Replay the interactions of the cassette, without sending requests
+	ModeReplay RecorderMode = iota
+	// Send the requests and record the interactions in the cassette
+	ModeRecord
+)
+
+// Match is a set of request parts compared to find the recorded interaction of a request
+type Match int
+
+const (
+	MatchMethod Match = 1 << iota
+	MatchPath
+	MatchQuery
+	// JSON bodies are compared semantically
+	MatchBody
+
+	MatchAll = MatchMethod | MatchPath | MatchQuery | MatchBody
+)
+
+// Cassette is the list of interactions recorded in a file
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request with its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request, with its secrets redacted
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response, with its secrets redacted
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// Whether the body is base64 encoded, for binary bodies like gzipped CSV exports
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// Recorder is an http.RoundTripper recording interactions to a cassette file and replaying them.
+// Use it with SetHTTPClient:
+//
+//	rec, err := onesignaltest.NewRecorder("testdata/notifications.json", onesignaltest.ModeReplay)
+//	...
+//	client.SetHTTPClient(rec.HTTPClient())
+//
+// The Authorization header, the auth hashes and the REST API keys of apps are redacted.
+// Requests are replayed in the order they were recorded when several interactions match.
+type Recorder struct {
+	// Transport sending the requests in record mode. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Request parts matched in replay mode. Defaults to MatchAll.
+	Match Match
+
+	path     string
+	mode     RecorderMode
+	mu       sync.Mutex
+	cassette Cassette
+	replayed map[*Interaction]bool
+}
+
+// NewRecorder returns a recorder of the cassette file at path.
+// In replay mode, the cassette is loaded from the file.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{
+		Match:    MatchAll,
+		path:     path,
+		mode:     mode,
+		replayed: map[*Interaction]bool{},
+	}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("onesignaltest: invalid cassette %s: %v", path, err)
+		}
+	}
+
+	return r, nil
+}
+
+// HTTPClient returns an HTTP client using the recorder as transport
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// RoundTrip records or replays the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	recorded := recordRequest(req, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, body, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte, recorded RecordedRequest) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	// send a copy, the original request must not be modified by a RoundTripper
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	res := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+	}
+	if utf8.Valid(respBody) {
+		res.Body = string(redactBody(respBody))
+	} else {
+		res.Body = base64.StdEncoding.EncodeToString(respBody)
+		res.Base64 = true
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{Request: recorded, Response: res})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, in := range r.cassette.Interactions {
+		if r.replayed[in] || !r.matches(recorded, in.Request) {
+			continue
+		}
+		r.replayed[in] = true
+
+		body := []byte(in.Response.Body)
+		if in.Response.Base64 {
+			var err error
+			if body, err = base64.StdEncoding.DecodeString(in.Response.Body); err != nil {
+				return nil, fmt.Errorf("onesignaltest: invalid base64 body in cassette %s: %v", r.path, err)
+			}
+		}
+
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("onesignaltest: no interaction of cassette %s matches %s %s", r.path, recorded.Method, recorded.URL)
+}
+
+// matches reports whether the request matches the recorded request
+func (r *Recorder) matches(req, recorded RecordedRequest) bool {
+	if r.Match&MatchMethod != 0 && req.Method != recorded.Method {
+		return false
+	}
+
+	u, err1 := url.Parse(req.URL)
+	ru, err2 := url.Parse(recorded.URL)
+	if err1 != nil || err2 != nil {
+		return req.URL == recorded.URL
+	}
+	if r.Match&MatchPath != 0 && u.Path != ru.Path {
+		return false
+	}
+	if r.Match&MatchQuery != 0 && !reflect.DeepEqual(u.Query(), ru.Query()) {
+		return false
+	}
+	if r.Match&MatchBody != 0 && !equalBodies(req.Body, recorded.Body) {
+		return false
+	}
+	return true
+}
+
+// recordRequest returns the request with its secrets redacted
+func recordRequest(req *http.Request, body []byte) RecordedRequest {
+	header := req.Header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", Redacted)
+	}
+
+	u := *req.URL
+	q := u.Query()
+	for k := range q {
+		if isSecret(k) {
+			q.Set(k, Redacted)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return RecordedRequest{
+		Method: req.Method,
+		URL:    u.String(),
+		Header: header,
+		Body:   string(redactBody(body)),
+	}
+}
+
+// redactBody replaces the secrets of a JSON body. Other bodies are returned unchanged.
+func redactBody(body []byte) []byte {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	if !redactJSON(v) {
+		return body
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactJSON replaces the secrets of a decoded JSON value, reporting whether it found any
+func redactJSON(v interface{}) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if _, ok := value.(string); ok && isSecret(k) {
+				v[k] = Redacted
+				found = true
+			} else if redactJSON(value) {
+				found = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactJSON(value) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// isSecret reports whether a JSON field or query parameter holds a secret
+func isSecret(name string) bool {
+	return strings.HasSuffix(name, "_auth_hash") || name == "basic_auth_key"
+}
+
+// equalBodies compares the bodies, semantically when both are JSON
+func equalBodies(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
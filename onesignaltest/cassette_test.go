@@ -0,0 +1,122 @@
+package onesignaltest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hgiasac/onesignal"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "players.json")
+
+	// record the interactions with the fake server
+	srv := NewServer()
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder returned an error: %v", err)
+	}
+	client := srv.Client()
+	client.SetHTTPClient(rec.HTTPClient())
+	client.SetIdentityVerification(true)
+
+	player := onesignal.PlayerRequest{AppID: srv.AppID, ExternalUserID: "user-1"}
+	created, _, err := client.Players.CreateEmail("foo@example.com", player)
+	if err != nil {
+		t.Fatalf("CreateEmail returned an error: %v", err)
+	}
+	if _, _, err := client.Players.Get("unknown-id"); !onesignal.IsNotFound(err) {
+		t.Fatalf("Get returned %v, want a not found error", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+	apiKey := srv.APIKey
+	srv.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{apiKey, onesignal.IdentityHash(apiKey, "foo@example.com"), onesignal.IdentityHash(apiKey, "user-1")} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains the secret %q", secret)
+		}
+	}
+
+	// replay them without server
+	rec, err = NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder returned an error: %v", err)
+	}
+	client, _ = onesignal.NewClient(srv.AppID, "other-api-key")
+	client.SetBaseURL(srv.URL)
+	client.SetHTTPClient(rec.HTTPClient())
+	client.SetIdentityVerification(true)
+
+	replayed, _, err := client.Players.CreateEmail("foo@example.com", player)
+	if err != nil {
+		t.Fatalf("CreateEmail returned an error: %v", err)
+	}
+	if replayed.ID != created.ID {
+		t.Errorf("CreateEmail returned %v, want %v", replayed.ID, created.ID)
+	}
+	if _, _, err := client.Players.Get("unknown-id"); !onesignal.IsNotFound(err) {
+		t.Errorf("Get returned %v, want a not found error", err)
+	}
+
+	// every interaction is replayed once
+	if _, _, err := client.Players.Get("unknown-id"); err == nil || onesignal.IsNotFound(err) {
+		t.Errorf("Get should fail without a matching interaction, got %v", err)
+	}
+}
+
+func TestRecorder_Match(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	err := ioutil.WriteFile(path, []byte(`{
+		"interactions": [{
+			"request": {
+				"method": "PUT",
+				"url": "https://onesignal.com/api/v1/players/player-id?app_id=app-id",
+				"body": "{\"app_id\": \"app-id\", \"tags\": {\"level\": \"1\"}}"
+			},
+			"response": {"status_code": 200, "body": "{\"success\": true}"}
+		}]
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		match Match
+		body  string
+		want  bool
+	}{
+		{"same body", MatchAll, `{"tags":{"level":"1"},"app_id":"app-id"}`, true},
+		{"other body", MatchAll, `{"app_id":"app-id","tags":{"level":"2"}}`, false},
+		{"body not matched", MatchMethod | MatchPath | MatchQuery, `{"app_id":"app-id","tags":{"level":"2"}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := NewRecorder(path, ModeReplay)
+			if err != nil {
+				t.Fatalf("NewRecorder returned an error: %v", err)
+			}
+			rec.Match = tt.match
+
+			req, _ := http.NewRequest("PUT", "http://localhost/api/v1/players/player-id?app_id=app-id", strings.NewReader(tt.body))
+			resp, err := rec.RoundTrip(req)
+			if got := err == nil; got != tt.want {
+				t.Fatalf("RoundTrip matched: %v, want %v (error: %v)", got, tt.want, err)
+			}
+			if err == nil && resp.StatusCode != http.StatusOK {
+				t.Errorf("StatusCode: %v, want %v", resp.StatusCode, http.StatusOK)
+			}
+		})
+	}
+}
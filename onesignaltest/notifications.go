@@ -0,0 +1,251 @@
+package onesignaltest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hgiasac/onesignal"
+)
+
+// maxNotificationsLimit is the maximum number of notifications returned by a list request
+const maxNotificationsLimit = 50
+
+// routeNotifications handles the /notifications endpoints
+func (s *Server) routeNotifications(w http.ResponseWriter, r *http.Request, a *app, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		s.listNotifications(w, r, a)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		s.createNotification(w, r, a)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.getNotification(w, a, parts[0])
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		s.trackOpen(w, r, a, parts[0])
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		s.cancelNotification(w, a, parts[0])
+	case len(parts) <= 1:
+		methodNotAllowed(w)
+	default:
+		writeErrors(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) listNotifications(w http.ResponseWriter, r *http.Request, a *app) {
+	limit, offset, ok := pagination(w, r, maxNotificationsLimit)
+	if !ok {
+		return
+	}
+
+	// every notification of the fake server is sent by the API
+	notifications := a.notifications
+	if kind := r.URL.Query().Get("kind"); kind != "" && kind != strconv.Itoa(int(onesignal.NotificationKindAPI)) {
+		notifications = nil
+	}
+
+	res := onesignal.NotificationListResponse{
+		TotalCount:    len(notifications),
+		Offset:        offset,
+		Limit:         limit,
+		Notifications: []onesignal.Notification{},
+	}
+	// the most recent notifications are listed first
+	for i := offset; i < len(notifications) && i < offset+limit; i++ {
+		res.Notifications = append(res.Notifications, *notifications[len(notifications)-1-i])
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getNotification(w http.ResponseWriter, a *app, id string) {
+	n := a.notification(id)
+	if n == nil {
+		writeErrors(w, http.StatusNotFound, "Couldn't find Notification with id="+id)
+		return
+	}
+	writeJSON(w, http.StatusOK, n)
+}
+
+func (s *Server) createNotification(w http.ResponseWriter, r *http.Request, a *app) {
+	var req onesignal.NotificationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.AppID != a.ID {
+		writeErrors(w, http.StatusBadRequest, "app_id not found. You may be using the wrong REST API key or App ID.")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeValidationErrors(w, err)
+		return
+	}
+
+	// notifications with the same external id are only created once
+	if req.ExternalID != "" {
+		for _, n := range a.notifications {
+			if n.ExternalID == req.ExternalID {
+				writeJSON(w, http.StatusOK, onesignal.NotificationCreateResponse{ID: n.ID, Recipients: n.Successful + n.Remaining})
+				return
+			}
+		}
+	}
+
+	recipients, invalid := a.recipients(&req)
+	if recipients == 0 {
+		errs := onesignal.NotificationErrors{Messages: []string{"All included players are not subscribed"}}
+		writeJSON(w, http.StatusOK, onesignal.NotificationCreateResponse{Errors: &errs})
+		return
+	}
+
+	now := int(s.now().Unix())
+	n := &onesignal.Notification{
+		NotificationRequest: req,
+		ID:                  newID(),
+		QueuedAt:            now,
+	}
+	if req.SendAfter != "" {
+		n.Remaining = recipients
+	} else {
+		n.Successful = recipients
+		n.SendAfter = now
+		n.CompletedAt = now
+	}
+	a.notifications = append(a.notifications, n)
+
+	res := onesignal.NotificationCreateResponse{ID: n.ID, Recipients: recipients}
+	if invalid.HasInvalidRecipients() {
+		res.Errors = &invalid
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// cancelNotification cancels a scheduled notification
+func (s *Server) cancelNotification(w http.ResponseWriter, a *app, id string) {
+	n := a.notification(id)
+	if n == nil {
+		writeErrors(w, http.StatusNotFound, "Couldn't find Notification with id="+id)
+		return
+	}
+	if n.Canceled {
+		writeErrors(w, http.StatusBadRequest, "Notification has already been canceled")
+		return
+	}
+	if n.Remaining == 0 {
+		writeErrors(w, http.StatusBadRequest, "Notification has already been sent to all recipients")
+		return
+	}
+	n.Canceled = true
+	n.Remaining = 0
+	writeSuccess(w)
+}
+
+// trackOpen records the click of a player on a notification
+func (s *Server) trackOpen(w http.ResponseWriter, r *http.Request, a *app, id string) {
+	var req struct {
+		AppID    string `json:"app_id"`
+		Opened   bool   `json:"opened"`
+		PlayerID string `json:"player_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	n := a.notification(id)
+	if n == nil {
+		writeErrors(w, http.StatusNotFound, "Couldn't find Notification with id="+id)
+		return
+	}
+	if req.Opened {
+		n.Converted++
+	}
+	writeSuccess(w)
+}
+
+func (a *app) notification(id string) *onesignal.Notification {
+	for _, n := range a.notifications {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// recipients counts the subscribed players targeted by the request, and lists the invalid recipients.
+// Segments, filters and tags aren't evaluated: they target every subscribed player of the channel.
+func (a *app) recipients(req *onesignal.NotificationRequest) (int, onesignal.NotificationErrors) {
+	channel := requestChannel(req)
+	targeted := map[string]bool{}
+	var invalid onesignal.NotificationErrors
+
+	for _, id := range req.IncludePlayerIDs {
+		if p := a.player(id); p != nil && subscribed(p) {
+			targeted[p.ID] = true
+		} else {
+			invalid.InvalidPlayerIDs = append(invalid.InvalidPlayerIDs, id)
+		}
+	}
+
+	externalChannel := channel
+	if req.ChannelForExternalUserIDs != "" {
+		externalChannel = req.ChannelForExternalUserIDs
+	}
+	for _, id := range req.IncludeExternalUserIDs {
+		found := false
+		for _, p := range a.players {
+			if p.ExternalUserID == id && playerChannel(p) == externalChannel && subscribed(p) {
+				targeted[p.ID] = true
+				found = true
+			}
+		}
+		if !found {
+			invalid.InvalidExternalUserIDs = append(invalid.InvalidExternalUserIDs, id)
+		}
+	}
+
+	for _, email := range req.IncludeEmailTokens {
+		if p := a.playerByIdentifier(onesignal.DeviceTypeEmail, email); p != nil && subscribed(p) {
+			targeted[p.ID] = true
+		} else {
+			invalid.InvalidEmailTokens = append(invalid.InvalidEmailTokens, email)
+		}
+	}
+
+	for _, phone := range req.IncludePhoneNumber {
+		if p := a.playerByIdentifier(onesignal.DeviceTypeSMS, phone); p != nil && subscribed(p) {
+			targeted[p.ID] = true
+		} else {
+			invalid.InvalidPhoneNumbers = append(invalid.InvalidPhoneNumbers, phone)
+		}
+	}
+
+	if len(req.IncludedSegments) > 0 || req.Filters != nil || req.Tags != nil {
+		for _, p := range a.players {
+			if playerChannel(p) == channel && subscribed(p) {
+				targeted[p.ID] = true
+			}
+		}
+	}
+
+	return len(targeted), invalid
+}
+
+// requestChannel returns the channel of the notification request
+func requestChannel(req *onesignal.NotificationRequest) onesignal.MessageType {
+	switch {
+	case req.EmailBody != "" || req.EmailSubject != "" || len(req.IncludeEmailTokens) > 0:
+		return onesignal.MessageTypeEmail
+	case req.SMSFrom != "" || len(req.SMSMediaURLs) > 0 || len(req.IncludePhoneNumber) > 0:
+		return onesignal.MessageTypeSMS
+	default:
+		return onesignal.MessageTypePush
+	}
+}
+
+// playerChannel returns the channel of the player
+func playerChannel(p *onesignal.Player) onesignal.MessageType {
+	switch p.DeviceType {
+	case onesignal.DeviceTypeEmail:
+		return onesignal.MessageTypeEmail
+	case onesignal.DeviceTypeSMS:
+		return onesignal.MessageTypeSMS
+	default:
+		return onesignal.MessageTypePush
+	}
+}
@@ -0,0 +1,359 @@
+package onesignaltest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hgiasac/onesignal"
+)
+
+// maxPlayersLimit is the maximum number of players returned by a list request
+const maxPlayersLimit = 300
+
+// routePlayers handles the /players endpoints
+func (s *Server) routePlayers(w http.ResponseWriter, r *http.Request, a *app, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		s.listPlayers(w, r, a)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		s.createPlayer(w, r, a)
+	case len(parts) == 0:
+		methodNotAllowed(w)
+	case len(parts) == 1 && parts[0] == "csv_export" && r.Method == http.MethodPost:
+		s.exportPlayers(w, r, a)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.getPlayer(w, a, parts[0])
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		s.updatePlayer(w, r, a, parts[0])
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		s.deletePlayer(w, a, parts[0])
+	case len(parts) == 1:
+		methodNotAllowed(w)
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		s.playerEvent(w, r, a, parts[0], parts[1])
+	default:
+		writeErrors(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) listPlayers(w http.ResponseWriter, r *http.Request, a *app) {
+	limit, offset, ok := pagination(w, r, maxPlayersLimit)
+	if !ok {
+		return
+	}
+
+	res := onesignal.PlayerListResponse{
+		TotalCount: len(a.players),
+		Offset:     offset,
+		Limit:      limit,
+		Players:    []onesignal.Player{},
+	}
+	for i := offset; i < len(a.players) && i < offset+limit; i++ {
+		res.Players = append(res.Players, *a.players[i])
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getPlayer(w http.ResponseWriter, a *app, id string) {
+	p := a.player(id)
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "No user with this id found")
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) createPlayer(w http.ResponseWriter, r *http.Request, a *app) {
+	var req onesignal.PlayerRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.AppID != a.ID {
+		writeErrors(w, http.StatusBadRequest, "app_id not found. You may be using the wrong REST API key or App ID.")
+		return
+	}
+	if req.DeviceType == onesignal.DeviceTypeEmail || req.DeviceType == onesignal.DeviceTypeSMS {
+		if req.Identifier == "" {
+			writeErrors(w, http.StatusBadRequest, "identifier is required for email and SMS devices")
+			return
+		}
+	}
+
+	// like OneSignal, a known identifier updates the existing player
+	p := a.playerByIdentifier(req.DeviceType, req.Identifier)
+	if p == nil {
+		now := int(s.now().Unix())
+		p = &onesignal.Player{ID: newID(), DeviceType: req.DeviceType, CreatedAt: now, LastActive: now, SessionCount: 1}
+		a.players = append(a.players, p)
+	}
+	applyPlayerRequest(p, req)
+
+	writeJSON(w, http.StatusOK, onesignal.PlayerCreateResponse{Success: true, ID: p.ID})
+}
+
+func (s *Server) updatePlayer(w http.ResponseWriter, r *http.Request, a *app, id string) {
+	var req onesignal.PlayerRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	p := a.player(id)
+	if p == nil {
+		writeErrors(w, http.StatusBadRequest, "No user with this id found")
+		return
+	}
+	applyPlayerRequest(p, req)
+	writeSuccess(w)
+}
+
+func (s *Server) deletePlayer(w http.ResponseWriter, a *app, id string) {
+	for i, p := range a.players {
+		if p.ID == id {
+			a.players = append(a.players[:i], a.players[i+1:]...)
+			writeSuccess(w)
+			return
+		}
+	}
+	writeErrors(w, http.StatusNotFound, "No user with this id found")
+}
+
+// playerEvent handles the on_session, on_purchase and on_focus endpoints
+func (s *Server) playerEvent(w http.ResponseWriter, r *http.Request, a *app, id, event string) {
+	p := a.player(id)
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "No user with this id found")
+		return
+	}
+
+	switch event {
+	case "on_session":
+		var opt onesignal.PlayerOnSessionOptions
+		if !decodeBody(w, r, &opt) {
+			return
+		}
+		applyPlayerRequest(p, onesignal.PlayerRequest{
+			Identifier:  opt.Identifier,
+			Language:    opt.Language,
+			Timezone:    opt.Timezone,
+			GameVersion: opt.GameVersion,
+			DeviceOS:    opt.DeviceOS,
+			AdID:        opt.AdID,
+			SDK:         opt.SDK,
+			Tags:        opt.Tags,
+		})
+		p.SessionCount++
+		p.LastActive = int(s.now().Unix())
+	case "on_purchase":
+		var opt onesignal.PlayerOnPurchaseOptions
+		if !decodeBody(w, r, &opt) {
+			return
+		}
+		// existing purchases are only recorded, without updating the amount spent
+		if !opt.Existing {
+			for _, purchase := range opt.Purchases {
+				p.AmountSpent += purchase.Amount
+			}
+		}
+	case "on_focus":
+		var opt onesignal.PlayerOnFocusOptions
+		if !decodeBody(w, r, &opt) {
+			return
+		}
+		if opt.State != "ping" {
+			writeErrors(w, http.StatusBadRequest, `state must be "ping"`)
+			return
+		}
+		p.Playtime += opt.ActiveTime
+	default:
+		writeErrors(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	writeSuccess(w)
+}
+
+// updateUserTags handles the tags update of the players of an external user id
+func (s *Server) updateUserTags(w http.ResponseWriter, r *http.Request, a *app, externalUserID string) {
+	var opt onesignal.UpdateTagsWithExternalUserIDOptions
+	if !decodeBody(w, r, &opt) {
+		return
+	}
+
+	found := false
+	for _, p := range a.players {
+		if p.ExternalUserID == externalUserID {
+			mergeTags(p, opt.Tags)
+			found = true
+		}
+	}
+	if !found {
+		writeErrors(w, http.StatusBadRequest, "User not found")
+		return
+	}
+	writeSuccess(w)
+}
+
+// exportPlayers generates the gzipped CSV export of the players, served by the exports endpoint
+func (s *Server) exportPlayers(w http.ResponseWriter, r *http.Request, a *app) {
+	var opt onesignal.PlayerCSVExportOptions
+	if r.ContentLength != 0 && !decodeBody(w, r, &opt) {
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	cw := csv.NewWriter(zw)
+	cw.Write([]string{
+		"id", "identifier", "session_count", "language", "timezone", "game_version", "device_os",
+		"device_type", "device_model", "ad_id", "tags", "last_active", "playtime", "amount_spent",
+		"created_at", "invalid_identifier", "badge_count", "sdk", "test_type", "ip",
+		"external_user_id", "notification_types", "country", "location", "rooted", "web_auth", "web_p256",
+	})
+	for _, p := range a.players {
+		if opt.LastActiveSince > 0 && p.LastActive < opt.LastActiveSince {
+			continue
+		}
+		tags, _ := json.Marshal(p.Tags)
+		cw.Write([]string{
+			p.ID, p.Identifier, strconv.Itoa(p.SessionCount), p.Language, strconv.Itoa(p.Timezone),
+			p.GameVersion, p.DeviceOS, strconv.Itoa(int(p.DeviceType)), p.DeviceModel, p.AdID, string(tags),
+			strconv.Itoa(p.LastActive), strconv.Itoa(p.Playtime), strconv.FormatFloat(float64(p.AmountSpent), 'f', 2, 32),
+			strconv.Itoa(p.CreatedAt), strconv.FormatBool(p.InvalidIdentifier), strconv.Itoa(p.BadgeCount),
+			p.SDK, strconv.Itoa(p.TestType), p.IP, p.ExternalUserID, strconv.Itoa(p.NotificationTypes),
+			p.Country, fmt.Sprintf("(%g, %g)", p.Lat, p.Long), strconv.FormatBool(p.Rooted), p.WebAuth, p.WebP256,
+		})
+	}
+	cw.Flush()
+	zw.Close()
+
+	name := newID() + ".csv.gz"
+	s.exports[name] = buf.Bytes()
+
+	writeJSON(w, http.StatusOK, onesignal.PlayerCSVExportResponse{CSVFileURL: s.URL + "/exports/" + name})
+}
+
+// serveExport serves a CSV export. Like the OneSignal storage, it doesn't require authentication.
+func (s *Server) serveExport(w http.ResponseWriter, r *http.Request, name string) {
+	data, ok := s.exports[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Write(data)
+}
+
+func (a *app) player(id string) *onesignal.Player {
+	for _, p := range a.players {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (a *app) playerByIdentifier(deviceType onesignal.DeviceType, identifier string) *onesignal.Player {
+	if identifier == "" {
+		return nil
+	}
+	for _, p := range a.players {
+		if p.DeviceType == deviceType && p.Identifier == identifier {
+			return p
+		}
+	}
+	return nil
+}
+
+// applyPlayerRequest sets the fields of the request on the player, merging the tags.
+// The device type is only set on creation, since updates always send it.
+func applyPlayerRequest(p *onesignal.Player, req onesignal.PlayerRequest) {
+	setString(&p.Identifier, req.Identifier)
+	setString(&p.Language, req.Language)
+	setString(&p.GameVersion, req.GameVersion)
+	setString(&p.DeviceOS, req.DeviceOS)
+	setString(&p.DeviceModel, req.DeviceModel)
+	setString(&p.AdID, req.AdID)
+	setString(&p.SDK, req.SDK)
+	setString(&p.Country, req.Country)
+	setString(&p.ExternalUserID, req.ExternalUserID)
+	if req.Timezone != 0 {
+		p.Timezone = req.Timezone
+	}
+	if req.SessionCount != 0 {
+		p.SessionCount = req.SessionCount
+	}
+	if req.AmountSpent != 0 {
+		p.AmountSpent = req.AmountSpent
+	}
+	if req.Playtime != 0 {
+		p.Playtime = req.Playtime
+	}
+	if req.LastActive != 0 {
+		p.LastActive = req.LastActive
+	}
+	if req.TestType != 0 {
+		p.TestType = req.TestType
+	}
+	if req.BadgeCount != 0 {
+		p.BadgeCount = req.BadgeCount
+	}
+	if req.Lat != 0 || req.Long != 0 {
+		p.Lat, p.Long = req.Lat, req.Long
+	}
+	if n, err := strconv.Atoi(req.NotificationTypes); err == nil {
+		p.NotificationTypes = n
+	}
+	mergeTags(p, req.Tags)
+}
+
+// mergeTags sets the tags on the player. Like OneSignal, empty values delete the tags.
+func mergeTags(p *onesignal.Player, tags map[string]string) {
+	for k, v := range tags {
+		if v == "" {
+			delete(p.Tags, k)
+			continue
+		}
+		if p.Tags == nil {
+			p.Tags = map[string]string{}
+		}
+		p.Tags[k] = v
+	}
+}
+
+func setString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// subscribed reports whether the player can receive notifications
+func subscribed(p *onesignal.Player) bool {
+	return !p.InvalidIdentifier && p.NotificationTypes >= 0
+}
+
+// pagination returns the limit and offset of the query string
+func pagination(w http.ResponseWriter, r *http.Request, maxLimit int) (int, int, bool) {
+	q := r.URL.Query()
+	limit, offset := maxLimit, 0
+	var err error
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			writeErrors(w, http.StatusBadRequest, "limit must be a positive integer")
+			return 0, 0, false
+		}
+		if limit == 0 || limit > maxLimit {
+			limit = maxLimit
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			writeErrors(w, http.StatusBadRequest, "offset must be a positive integer")
+			return 0, 0, false
+		}
+	}
+	return limit, offset, true
+}
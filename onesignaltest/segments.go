@@ -0,0 +1,49 @@
+package onesignaltest
+
+import (
+	"net/http"
+
+	"github.com/hgiasac/onesignal"
+)
+
+// routeSegments handles the /apps/{app_id}/segments endpoints
+func (s *Server) routeSegments(w http.ResponseWriter, r *http.Request, a *app, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		s.createSegment(w, r, a)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		s.deleteSegment(w, a, parts[0])
+	case len(parts) <= 1:
+		methodNotAllowed(w)
+	default:
+		writeErrors(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) createSegment(w http.ResponseWriter, r *http.Request, a *app) {
+	var req onesignal.SegmentRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeValidationErrors(w, err)
+		return
+	}
+
+	// like OneSignal, segments with the same name are allowed
+	seg := &Segment{ID: newID(), Name: req.Name, Filters: req.Filters}
+	a.segments = append(a.segments, seg)
+
+	writeJSON(w, http.StatusOK, onesignal.SegmentCreateResponse{Success: true, ID: seg.ID})
+}
+
+func (s *Server) deleteSegment(w http.ResponseWriter, a *app, id string) {
+	for i, seg := range a.segments {
+		if seg.ID == id {
+			a.segments = append(a.segments[:i], a.segments[i+1:]...)
+			writeSuccess(w)
+			return
+		}
+	}
+	writeErrors(w, http.StatusNotFound, "Segment not found")
+}
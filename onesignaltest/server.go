@@ -0,0 +1,437 @@
+// Package onesignaltest provides an in-memory fake of the OneSignal API and a recorder
+// of API interactions for integration tests.
+//
+// The fake server stores apps, players, notifications and segments in memory,
+// returns the error bodies of the OneSignal API, and can inject latency and failures.
+// Point a client at it with SetBaseURL, or use the Client and UserClient helpers:
+//
+//	srv := onesignaltest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	res, _, err := client.Notifications.Create(&onesignal.NotificationRequest{...})
+//
+// Segments and filters are not evaluated: notifications sent to segments or filters
+// target every player of the app.
+//
+// Tests which need the responses of the real API use a Recorder instead. In ModeRecord it sends
+// the requests to OneSignal and saves the interactions, with their secrets redacted, to a cassette
+// file; in ModeReplay it answers the requests from the cassette without network access.
+package onesignaltest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hgiasac/onesignal"
+)
+
+// Server is a fake OneSignal API server.
+type Server struct {
+	// URL of the server, to use with SetBaseURL
+	URL string
+	// ID of the default app
+	AppID string
+	// REST API key of the default app
+	APIKey string
+	// User auth key, for the apps endpoints
+	UserKey string
+
+	server *httptest.Server
+
+	mu      sync.Mutex
+	latency time.Duration
+	faults  []*Fault
+	apps    []*app
+	exports map[string][]byte
+	now     func() time.Time
+}
+
+// app stores the state of an app
+type app struct {
+	onesignal.App
+	players       []*onesignal.Player
+	notifications []*onesignal.Notification
+	segments      []*Segment
+}
+
+// Segment is a segment stored by the server
+type Segment struct {
+	ID      string
+	Name    string
+	Filters onesignal.Filters
+}
+
+// Fault makes the server fail the matching requests
+type Fault struct {
+	// HTTP method of the failing requests. Empty matches all methods.
+	Method string
+	// Path prefix of the failing requests, like "/notifications". Empty matches all paths.
+	Path string
+	// Status code of the response
+	StatusCode int
+	// Headers of the response, like Retry-After
+	Header http.Header
+	// Body of the response. Defaults to an OneSignal error body with the status text.
+	Body string
+	// Number of requests to fail. Zero fails all the matching requests.
+	Times int
+}
+
+// NewServer starts a fake server with a default app
+func NewServer() *Server {
+	s := &Server{
+		UserKey: newID(),
+		exports: map[string][]byte{},
+		now:     time.Now,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+
+	a := s.AddApp("Test App")
+	s.AppID = a.ID
+	s.APIKey = a.BasicAuthKey
+
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a client of the default app, pointed at the server
+func (s *Server) Client() *onesignal.Client {
+	c, err := onesignal.NewClient(s.AppID, s.APIKey)
+	if err != nil {
+		panic(err)
+	}
+	c.SetBaseURL(s.URL)
+	return c
+}
+
+// UserClient returns a user client pointed at the server
+func (s *Server) UserClient() *onesignal.UserClient {
+	c, err := onesignal.NewUserClient(s.UserKey)
+	if err != nil {
+		panic(err)
+	}
+	c.SetBaseURL(s.URL)
+	return c
+}
+
+// SetLatency delays every response
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// InjectFault makes the server fail the requests matching the fault
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes the injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// AddApp adds an app and returns it, with its ID and REST API key
+func (s *Server) AddApp(name string) onesignal.App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	a := &app{App: onesignal.App{
+		ID:           newID(),
+		Name:         name,
+		BasicAuthKey: newID(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}}
+	s.apps = append(s.apps, a)
+	return a.App
+}
+
+// AddPlayer adds a player to an app and returns it, with its ID
+func (s *Server) AddPlayer(appID string, player onesignal.Player) onesignal.Player {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.appByID(appID)
+	if a == nil {
+		panic(fmt.Sprintf("onesignaltest: unknown app %q", appID))
+	}
+	if player.ID == "" {
+		player.ID = newID()
+	}
+	a.players = append(a.players, &player)
+	return player
+}
+
+// Players returns the players of an app
+func (s *Server) Players(appID string) []onesignal.Player {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var players []onesignal.Player
+	if a := s.appByID(appID); a != nil {
+		for _, p := range a.players {
+			players = append(players, *p)
+		}
+	}
+	return players
+}
+
+// Notifications returns the notifications of an app
+func (s *Server) Notifications(appID string) []onesignal.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notifications []onesignal.Notification
+	if a := s.appByID(appID); a != nil {
+		for _, n := range a.notifications {
+			notifications = append(notifications, *n)
+		}
+	}
+	return notifications
+}
+
+// Segments returns the segments of an app
+func (s *Server) Segments(appID string) []Segment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var segments []Segment
+	if a := s.appByID(appID); a != nil {
+		for _, seg := range a.segments {
+			segments = append(segments, *seg)
+		}
+	}
+	return segments
+}
+
+func (s *Server) appByID(id string) *app {
+	for _, a := range s.apps {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *Server) appByKey(key string) *app {
+	for _, a := range s.apps {
+		if a.BasicAuthKey == key {
+			return a
+		}
+	}
+	return nil
+}
+
+// serveHTTP applies the latency and the faults, then routes the request
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	latency := s.latency
+	s.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f := s.matchFault(r); f != nil {
+		for k, v := range f.Header {
+			w.Header()[k] = v
+		}
+		body := f.Body
+		if body == "" {
+			b, _ := json.Marshal(errorBody{Errors: []string{http.StatusText(f.StatusCode)}})
+			body = string(b)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.StatusCode)
+		fmt.Fprint(w, body)
+		return
+	}
+
+	s.route(w, r)
+}
+
+// matchFault returns the fault matching the request, if any
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// route dispatches the request to the handler of its path
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case parts[0] == "exports" && len(parts) == 2:
+		s.serveExport(w, r, parts[1])
+	case parts[0] == "apps" && len(parts) <= 2:
+		s.routeApps(w, r, parts[1:])
+	case parts[0] == "apps":
+		a, ok := s.authorizeApp(w, r, parts[1])
+		if !ok {
+			return
+		}
+		switch {
+		case len(parts) >= 3 && parts[2] == "segments":
+			s.routeSegments(w, r, a, parts[3:])
+		case len(parts) == 4 && parts[2] == "users" && r.Method == http.MethodPut:
+			s.updateUserTags(w, r, a, parts[3])
+		default:
+			writeErrors(w, http.StatusNotFound, "Not found")
+		}
+	case parts[0] == "players":
+		a, ok := s.authorizeApp(w, r, "")
+		if !ok {
+			return
+		}
+		s.routePlayers(w, r, a, parts[1:])
+	case parts[0] == "notifications":
+		a, ok := s.authorizeApp(w, r, "")
+		if !ok {
+			return
+		}
+		s.routeNotifications(w, r, a, parts[1:])
+	default:
+		writeErrors(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// authorizeApp returns the app of the REST API key of the request.
+// The app id of the path, of the query string or of the body must be the id of the app.
+func (s *Server) authorizeApp(w http.ResponseWriter, r *http.Request, appID string) (*app, bool) {
+	key, ok := basicKey(r)
+	if !ok {
+		writeErrors(w, http.StatusBadRequest, "Please include a case-sensitive header of Authorization: Basic <YOUR-REST-API-KEY-HERE> with a valid REST API key.")
+		return nil, false
+	}
+
+	a := s.appByKey(key)
+	if a == nil {
+		writeErrors(w, http.StatusForbidden, "Access denied.  Please include an 'Authorization: Basic <YOUR-REST-API-KEY-HERE>' header with a valid REST API key.")
+		return nil, false
+	}
+
+	if appID == "" {
+		appID = r.URL.Query().Get("app_id")
+	}
+	if appID != "" && appID != a.ID {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("app_id %q not found. You may be using the wrong REST API key or App ID.", appID))
+		return nil, false
+	}
+
+	return a, true
+}
+
+// authorizeUser checks the user auth key of the request
+func (s *Server) authorizeUser(w http.ResponseWriter, r *http.Request) bool {
+	key, ok := basicKey(r)
+	if !ok || key != s.UserKey {
+		writeErrors(w, http.StatusForbidden, "Access denied.  Please include an 'Authorization: Basic <YOUR-USER-AUTH-KEY-HERE>' header with a valid User Auth Key.")
+		return false
+	}
+	return true
+}
+
+func basicKey(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Basic ") {
+		return "", false
+	}
+	key := strings.TrimPrefix(auth, "Basic ")
+	return key, key != ""
+}
+
+// errorBody is the body of the error responses of the API
+type errorBody struct {
+	Errors []string `json:"errors"`
+}
+
+func writeErrors(w http.ResponseWriter, status int, messages ...string) {
+	writeJSON(w, status, errorBody{Errors: messages})
+}
+
+// writeValidationErrors writes the messages of the validation errors of a request
+func writeValidationErrors(w http.ResponseWriter, err error) {
+	var verrs onesignal.ValidationErrors
+	if !errors.As(err, &verrs) {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	messages := make([]string, len(verrs))
+	for i, e := range verrs {
+		messages[i] = e.Error()
+	}
+	writeErrors(w, http.StatusBadRequest, messages...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeSuccess(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, onesignal.SuccessResponse{Success: true})
+}
+
+// decodeBody decodes the JSON body of the request, writing an error response on failure
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %v", err))
+		return false
+	}
+	return true
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeErrors(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// newID returns a random version 4 UUID
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
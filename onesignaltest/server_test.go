@@ -0,0 +1,284 @@
+package onesignaltest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hgiasac/onesignal"
+)
+
+func TestServer_players(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	created, _, err := client.Players.Create(onesignal.PlayerRequest{
+		AppID:          srv.AppID,
+		DeviceType:     onesignal.DeviceTypeAndroid,
+		Identifier:     "push-token",
+		ExternalUserID: "user-1",
+		Tags:           map[string]string{"level": "1", "team": "red"},
+	})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	if _, _, err := client.Players.Update(created.ID, onesignal.PlayerRequest{
+		DeviceType: onesignal.DeviceTypeAndroid,
+		Tags:       map[string]string{"level": "2", "team": ""},
+	}); err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+	if _, _, err := client.Players.OnPurchase(created.ID, onesignal.PlayerOnPurchaseOptions{
+		Purchases: []onesignal.Purchase{{SKU: "sku", Amount: 1.5, ISO: "USD"}},
+	}); err != nil {
+		t.Fatalf("OnPurchase returned an error: %v", err)
+	}
+
+	player, _, err := client.Players.Get(created.ID)
+	if err != nil {
+		t.Fatalf("Get returned an error: %v", err)
+	}
+	if want := map[string]string{"level": "2"}; !reflect.DeepEqual(player.Tags, want) {
+		t.Errorf("Tags: %v, want %v", player.Tags, want)
+	}
+	if player.DeviceType != onesignal.DeviceTypeAndroid || player.AmountSpent != 1.5 {
+		t.Errorf("Get returned %+v", player)
+	}
+
+	list, _, err := client.Players.List(&onesignal.PlayerListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("List returned an error: %v", err)
+	}
+	if list.TotalCount != 1 || len(list.Players) != 1 || list.Players[0].ID != created.ID {
+		t.Errorf("List returned %+v", list)
+	}
+
+	if _, _, err := client.Players.Delete(created.ID); err != nil {
+		t.Fatalf("Delete returned an error: %v", err)
+	}
+	if _, _, err := client.Players.Get(created.ID); !onesignal.IsNotFound(err) {
+		t.Errorf("Get of a deleted player returned %v, want a not found error", err)
+	}
+}
+
+func TestServer_csvExport(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	want := srv.AddPlayer(srv.AppID, onesignal.Player{
+		DeviceType: onesignal.DeviceTypeIOS,
+		Identifier: "push-token",
+		Tags:       map[string]string{"level": "1"},
+		LastActive: 1600000000,
+	})
+
+	it, err := client.Players.DownloadCSVExport(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("DownloadCSVExport returned an error: %v", err)
+	}
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		p := it.Player()
+		ids = append(ids, p.ID)
+		if !reflect.DeepEqual(p.Tags, want.Tags) || p.LastActive != want.LastActive {
+			t.Errorf("Player: %+v, want %+v", p, want)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{want.ID}) {
+		t.Errorf("Exported players: %v, want %v", ids, []string{want.ID})
+	}
+}
+
+func TestServer_notifications(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	player := srv.AddPlayer(srv.AppID, onesignal.Player{DeviceType: onesignal.DeviceTypeIOS, Identifier: "token"})
+	srv.AddPlayer(srv.AppID, onesignal.Player{DeviceType: onesignal.DeviceTypeIOS, Identifier: "unsubscribed", NotificationTypes: -2})
+
+	req := &onesignal.NotificationRequest{
+		Contents:         map[string]string{"en": "Hello"},
+		IncludePlayerIDs: []string{player.ID, "unknown-id"},
+		ExternalID:       "4ea6ff62-3bde-4bd2-8a32-16dd9e0b7c8c",
+	}
+	res, _, err := client.Notifications.Create(req)
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if res.Recipients != 1 || res.Errors == nil || !reflect.DeepEqual(res.Errors.InvalidPlayerIDs, []string{"unknown-id"}) {
+		t.Errorf("Create returned %+v", res)
+	}
+
+	// the external id makes the creation idempotent
+	again, _, err := client.Notifications.Create(req)
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if again.ID != res.ID || len(srv.Notifications(srv.AppID)) != 1 {
+		t.Errorf("Create with the same external id returned %+v, want %v", again, res.ID)
+	}
+
+	if _, _, err := client.Notifications.TrackOpen(res.ID, player.ID); err != nil {
+		t.Fatalf("TrackOpen returned an error: %v", err)
+	}
+	notification, _, err := client.Notifications.Get(res.ID)
+	if err != nil {
+		t.Fatalf("Get returned an error: %v", err)
+	}
+	if notification.Successful != 1 || notification.Converted != 1 || notification.Contents["en"] != "Hello" {
+		t.Errorf("Get returned %+v", notification)
+	}
+
+	// the notification was already sent
+	if _, _, err := client.Notifications.Delete(res.ID); !onesignal.IsStatus(err, http.StatusBadRequest) {
+		t.Errorf("Delete returned %v, want a bad request error", err)
+	}
+
+	none, _, err := client.Notifications.Create(&onesignal.NotificationRequest{
+		Contents:               map[string]string{"en": "Hello"},
+		IncludeExternalUserIDs: []string{"unknown-user"},
+	})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if none.ID != "" || !none.Errors.AllPlayersNotSubscribed {
+		t.Errorf("Create without subscribed players returned %+v", none)
+	}
+}
+
+func TestServer_notificationValidation(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	_, _, err := srv.Client().Notifications.Create(&onesignal.NotificationRequest{
+		IncludedSegments: []string{"Subscribed Users"},
+	})
+	apiErr, ok := onesignal.AsAPIError(err)
+	if !ok {
+		t.Fatalf("Create returned %v, want an API error", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || len(apiErr.Messages) == 0 {
+		t.Errorf("Create returned %+v", apiErr)
+	}
+}
+
+func TestServer_segments(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	res, _, err := client.Segments.Create(&onesignal.SegmentRequest{
+		Name:    "Active",
+		Filters: onesignal.Filters{onesignal.FilterSessionCount(onesignal.FilterRelationGreaterThan, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if segments := srv.Segments(srv.AppID); len(segments) != 1 || segments[0].ID != res.ID {
+		t.Errorf("Segments: %+v, want %v", segments, res.ID)
+	}
+
+	// segment names aren't unique
+	other, _, err := client.Segments.Create(&onesignal.SegmentRequest{
+		Name:    "Active",
+		Filters: onesignal.Filters{onesignal.FilterSessionCount(onesignal.FilterRelationGreaterThan, 5)},
+	})
+	if err != nil {
+		t.Fatalf("Create of a segment with the same name returned an error: %v", err)
+	}
+	if segments := srv.Segments(srv.AppID); len(segments) != 2 || segments[1].ID != other.ID || other.ID == res.ID {
+		t.Errorf("Segments: %+v, want %v and %v", segments, res.ID, other.ID)
+	}
+
+	if _, _, err := client.Segments.Delete(res.ID); err != nil {
+		t.Fatalf("Delete returned an error: %v", err)
+	}
+	if _, _, err := client.Segments.Delete(res.ID); !onesignal.IsNotFound(err) {
+		t.Errorf("Delete of a deleted segment returned %v, want a not found error", err)
+	}
+	if segments := srv.Segments(srv.AppID); len(segments) != 1 || segments[0].ID != other.ID {
+		t.Errorf("Segments after Delete: %+v, want %v", segments, other.ID)
+	}
+}
+
+func TestServer_apps(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	userClient := srv.UserClient()
+
+	app, _, err := userClient.Apps.Create(onesignal.AppRequest{Name: "Other App"})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if _, _, err := userClient.Apps.Update(app.ID, onesignal.AppRequest{SiteName: "Site"}); err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+
+	apps, _, err := userClient.Apps.List()
+	if err != nil {
+		t.Fatalf("List returned an error: %v", err)
+	}
+	if len(apps) != 2 || apps[1].Name != "Other App" || apps[1].SiteName != "Site" {
+		t.Errorf("List returned %+v", apps)
+	}
+
+	// the REST API key of an app can't be used for another app
+	client, _ := onesignal.NewClient(srv.AppID, app.BasicAuthKey)
+	client.SetBaseURL(srv.URL)
+	if _, _, err := client.Players.List(&onesignal.PlayerListOptions{}); !onesignal.IsStatus(err, http.StatusBadRequest) {
+		t.Errorf("List with the key of another app returned %v, want a bad request error", err)
+	}
+
+	// the REST API key can't manage apps
+	userClient, _ = onesignal.NewUserClient(srv.APIKey)
+	userClient.SetBaseURL(srv.URL)
+	if _, _, err := userClient.Apps.List(); !onesignal.IsStatus(err, http.StatusForbidden) {
+		t.Errorf("List with a REST API key returned %v, want a forbidden error", err)
+	}
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	srv.InjectFault(Fault{
+		Method:     http.MethodGet,
+		Path:       "/players",
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"1"}},
+		Times:      1,
+	})
+
+	if _, _, err := client.Players.List(&onesignal.PlayerListOptions{}); !onesignal.IsRateLimited(err) {
+		t.Errorf("List returned %v, want a rate limit error", err)
+	}
+	if _, _, err := client.Players.List(&onesignal.PlayerListOptions{}); err != nil {
+		t.Errorf("List after the fault returned an error: %v", err)
+	}
+}
+
+func TestServer_SetLatency(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.Players.ListContext(ctx, &onesignal.PlayerListOptions{}); err == nil {
+		t.Errorf("ListContext should fail when the latency exceeds the deadline")
+	}
+}
@@ -0,0 +1,154 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// OutcomeAggregation is the aggregation of the values of an outcome
+type OutcomeAggregation string
+
+// OutcomeTimeRange is the time range of the outcome data
+type OutcomeTimeRange string
+
+// OutcomeAttribution is the attribution type of the outcomes
+type OutcomeAttribution string
+
+const (
+	OutcomeAggregationCount OutcomeAggregation = "count"
+	OutcomeAggregationSum   OutcomeAggregation = "sum"
+
+	// Data of the last hour
+	OutcomeTimeRangeHour OutcomeTimeRange = "1h"
+	// Data of the last day
+	OutcomeTimeRangeDay OutcomeTimeRange = "1d"
+	// Data of the last month
+	OutcomeTimeRangeMonth OutcomeTimeRange = "1mo"
+
+	OutcomeAttributionDirect       OutcomeAttribution = "direct"
+	OutcomeAttributionInfluenced   OutcomeAttribution = "influenced"
+	OutcomeAttributionUnattributed OutcomeAttribution = "unattributed"
+	// Sum of the direct, influenced and unattributed outcomes
+	OutcomeAttributionTotal OutcomeAttribution = "total"
+
+	// Built-in outcome of the clicks on notifications
+	OutcomeClick = "os__click"
+	// Built-in outcome of the duration of the sessions, in seconds
+	OutcomeSessionDuration = "os__session_duration"
+)
+
+// OutcomeName is the name of an outcome with the aggregation of its values
+type OutcomeName struct {
+	Name        string
+	Aggregation OutcomeAggregation
+}
+
+// OutcomeCount requests the number of times an outcome occurred
+func OutcomeCount(name string) OutcomeName {
+	return OutcomeName{Name: name, Aggregation: OutcomeAggregationCount}
+}
+
+// OutcomeSum requests the sum of the values of an outcome
+func OutcomeSum(name string) OutcomeName {
+	return OutcomeName{Name: name, Aggregation: OutcomeAggregationSum}
+}
+
+// String returns the name in the name.aggregation format of the API
+func (n OutcomeName) String() string {
+	return fmt.Sprintf("%s.%s", n.Name, n.Aggregation)
+}
+
+// Outcome is the aggregated value of an outcome.
+// The sum of outcomes sent with a value, like the amount of a purchase, can be fractional.
+type Outcome struct {
+	ID          string             `json:"id"`
+	Value       float64            `json:"value"`
+	Aggregation OutcomeAggregation `json:"aggregation"`
+}
+
+// OutcomesService handles communication with the outcome related
+// methods of the OneSignal API.
+type OutcomesService struct {
+	client *Client
+}
+
+// OutcomeOptions specifies the parameters to the OutcomesService.List method
+type OutcomeOptions struct {
+	// Outcomes to return. Required.
+	Names []OutcomeName
+	// Time range of the data. Defaults to the last hour.
+	TimeRange OutcomeTimeRange
+	// Platforms of the outcomes. Defaults to all platforms.
+	Platforms []DeviceType
+	// Attribution of the outcomes. Defaults to total.
+	Attribution OutcomeAttribution
+}
+
+// OutcomesResponse wraps the standard http.Response for the
+// OutcomesService.List method
+type OutcomesResponse struct {
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// List the aggregated outcomes of the app.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/view-outcomes
+func (s *OutcomesService) List(opt *OutcomeOptions) (*OutcomesResponse, *http.Response, error) {
+	return s.ListContext(context.Background(), opt)
+}
+
+// ListContext lists the aggregated outcomes of the app with the provided context.
+func (s *OutcomesService) ListContext(ctx context.Context, opt *OutcomeOptions) (*OutcomesResponse, *http.Response, error) {
+	// build the URL with the query string
+	u, err := url.Parse(fmt.Sprintf("/apps/%s/outcomes", s.client.appID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, len(opt.Names))
+	for i, n := range opt.Names {
+		names[i] = n.String()
+	}
+	platforms := make([]string, len(opt.Platforms))
+	for i, p := range opt.Platforms {
+		platforms[i] = strconv.Itoa(int(p))
+	}
+
+	q := u.Query()
+	setOutcomeQuery(q, names, string(opt.TimeRange), strings.Join(platforms, ","), string(opt.Attribution))
+	u.RawQuery = q.Encode()
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outcomesRes := &OutcomesResponse{}
+	resp, err := s.client.Do(req, outcomesRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return outcomesRes, resp, err
+}
+
+// setOutcomeQuery sets the outcome parameters of the query string, skipping empty values
+func setOutcomeQuery(q url.Values, names []string, timeRange, platforms, attribution string) {
+	if len(names) > 0 {
+		q.Set("outcome_names", strings.Join(names, ","))
+	}
+	if timeRange != "" {
+		q.Set("outcome_time_range", timeRange)
+	}
+	if platforms != "" {
+		q.Set("outcome_platforms", platforms)
+	}
+	if attribution != "" {
+		q.Set("outcome_attribution", attribution)
+	}
+}
@@ -0,0 +1,98 @@
+package onesignal
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestOutcomesService_List(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	requestSent := false
+
+	mux.HandleFunc("/apps/fake-app-id/outcomes", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "GET")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+
+		want := url.Values{
+			"outcome_names":       {"os__click.count,os__session_duration.sum,purchase.sum"},
+			"outcome_time_range":  {"1d"},
+			"outcome_platforms":   {"0,1"},
+			"outcome_attribution": {"direct"},
+		}
+		if got := r.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Query: %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, `{
+			"outcomes": [
+				{"id": "os__click", "value": 12, "aggregation": "count"},
+				{"id": "os__session_duration", "value": 3600, "aggregation": "sum"},
+				{"id": "purchase", "value": 18.76, "aggregation": "sum"}
+			]
+		}`)
+	})
+
+	res, _, err := client.Outcomes.List(&OutcomeOptions{
+		Names: []OutcomeName{
+			OutcomeCount(OutcomeClick),
+			OutcomeSum(OutcomeSessionDuration),
+			OutcomeSum("purchase"),
+		},
+		TimeRange:   OutcomeTimeRangeDay,
+		Platforms:   []DeviceType{DeviceTypeIOS, DeviceTypeAndroid},
+		Attribution: OutcomeAttributionDirect,
+	})
+	if err != nil {
+		t.Errorf("List returned an error: %v", err)
+	}
+
+	want := &OutcomesResponse{
+		Outcomes: []Outcome{
+			{ID: "os__click", Value: 12, Aggregation: OutcomeAggregationCount},
+			{ID: "os__session_duration", Value: 3600, Aggregation: OutcomeAggregationSum},
+			{ID: "purchase", Value: 18.76, Aggregation: OutcomeAggregationSum},
+		},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("List returned %+v, want %+v", res, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestNotificationsService_Get_outcomes(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/notifications/notif-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("outcome_names"), "os__click.count,purchase.sum"; got != want {
+			t.Errorf("outcome_names: %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, `{
+			"id": "notif-fake-id",
+			"outcomes": [{"id": "os__click", "value": 3, "aggregation": "count"}]
+		}`)
+	})
+
+	notification, _, err := client.Notifications.Get("notif-fake-id", NotificationGetOptions{
+		OutcomeNames: []string{OutcomeCount(OutcomeClick).String(), OutcomeSum("purchase").String()},
+	})
+	if err != nil {
+		t.Fatalf("Get returned an error: %v", err)
+	}
+
+	want := []Outcome{{ID: "os__click", Value: 3, Aggregation: OutcomeAggregationCount}}
+	if !reflect.DeepEqual(notification.Outcomes, want) {
+		t.Errorf("Outcomes: %+v, want %+v", notification.Outcomes, want)
+	}
+}
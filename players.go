@@ -1,12 +1,40 @@
 package onesignal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/mail"
 	"net/url"
+	"regexp"
 	"strconv"
 )
 
+// e164Pattern matches phone numbers in E.164 format, like +15555550100
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// DeviceType is the platform of a player
+type DeviceType int
+
+const (
+	DeviceTypeIOS          DeviceType = 0
+	DeviceTypeAndroid      DeviceType = 1
+	DeviceTypeAmazon       DeviceType = 2
+	DeviceTypeWindowsPhone DeviceType = 3
+	// Chrome Apps & Extensions
+	DeviceTypeChromeApp DeviceType = 4
+	// Chrome web push, including Chrome on Android
+	DeviceTypeChromeWeb  DeviceType = 5
+	DeviceTypeWindowsWNS DeviceType = 6
+	DeviceTypeSafari     DeviceType = 7
+	DeviceTypeFirefox    DeviceType = 8
+	DeviceTypeMacOS      DeviceType = 9
+	DeviceTypeAlexa      DeviceType = 10
+	DeviceTypeEmail      DeviceType = 11
+	DeviceTypeHuawei     DeviceType = 13
+	DeviceTypeSMS        DeviceType = 14
+)
+
 // PlayersService handles communication with the player related
 // methods of the OneSignal API.
 type PlayersService struct {
@@ -24,7 +52,7 @@ type Player struct {
 	Timezone          int               `json:"timezone"`
 	GameVersion       string            `json:"game_version"`
 	DeviceOS          string            `json:"device_os"`
-	DeviceType        int               `json:"device_type"`
+	DeviceType        DeviceType        `json:"device_type"`
 	DeviceModel       string            `json:"device_model"`
 	AdID              string            `json:"ad_id"`
 	Tags              map[string]string `json:"tags"`
@@ -36,13 +64,24 @@ type Player struct {
 	TestType          int               `json:"test_type,omitempty"`
 	IP                string            `json:"ip,omitempty"`
 	ExternalUserID    string            `json:"external_user_id,omitempty"`
+	NotificationTypes int               `json:"notification_types,omitempty"`
+	// Country code in the ISO 3166-1 Alpha 2 format
+	Country string `json:"country,omitempty"`
+	// Latitude and longitude of the device
+	Lat  float64 `json:"lat,omitempty"`
+	Long float64 `json:"long,omitempty"`
+	// Whether the Android device is rooted
+	Rooted bool `json:"rooted,omitempty"`
+	// Web push auth and p256 keys
+	WebAuth string `json:"web_auth,omitempty"`
+	WebP256 string `json:"web_p256,omitempty"`
 }
 
 // PlayerRequest represents a request to create/update a player.
 type PlayerRequest struct {
 	AppID string `json:"app_id"`
 	// Required The device's platform:
-	DeviceType int `json:"device_type"`
+	DeviceType DeviceType `json:"device_type"`
 	// For Push Notifications, this is the Push Token Identifier from Google or Apple.
 	// For Apple Push identifiers, you must strip all non alphanumeric characters.
 	Identifier string `json:"identifier,omitempty"`
@@ -115,6 +154,8 @@ type PlayerListOptions struct {
 // PlayerGetOptions specifies the parameters to the PlayersService.Get method
 type PlayerGetOptions struct {
 	EmailAuthHash string `json:"email_auth_hash"`
+	// Email address of the player, used to compute EmailAuthHash when identity verification is enabled
+	Email string `json:"-"`
 }
 
 // UpdateTagsWithExternalUserIDOptions specifies the parameters to the PlayersService.UpdateTagsWithExternalUserID method
@@ -176,6 +217,7 @@ type PlayerOnFocusOptions struct {
 // PlayerCSVExportOptions specifies the parameters to the
 // PlayersService.CSVExport method
 type PlayerCSVExportOptions struct {
+	// Additional columns of the export: location, country, rooted, ip, web_auth, web_p256, notification_types, external_user_id
 	ExtraFields     []string `json:"extra_fields"`
 	LastActiveSince int      `json:"last_active_since"`
 	SegmentName     string   `json:"segment_name"`
@@ -191,6 +233,11 @@ type PlayerCSVExportResponse struct {
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/players-view-devices
 func (s *PlayersService) List(opt *PlayerListOptions) (*PlayerListResponse, *http.Response, error) {
+	return s.ListContext(context.Background(), opt)
+}
+
+// ListContext lists the players with the provided context.
+func (s *PlayersService) ListContext(ctx context.Context, opt *PlayerListOptions) (*PlayerListResponse, *http.Response, error) {
 	// build the URL with the query string
 	u, err := url.Parse("/players")
 	if err != nil {
@@ -203,7 +250,7 @@ func (s *PlayersService) List(opt *PlayerListOptions) (*PlayerListResponse, *htt
 	u.RawQuery = q.Encode()
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -221,6 +268,11 @@ func (s *PlayersService) List(opt *PlayerListOptions) (*PlayerListResponse, *htt
 //
 // OneSignal API docs: https://documentation.onesignal.com/reference/view-device
 func (s *PlayersService) Get(playerID string, opt ...PlayerGetOptions) (*Player, *http.Response, error) {
+	return s.GetContext(context.Background(), playerID, opt...)
+}
+
+// GetContext gets a single player with the provided context.
+func (s *PlayersService) GetContext(ctx context.Context, playerID string, opt ...PlayerGetOptions) (*Player, *http.Response, error) {
 	// build the URL
 	path := fmt.Sprintf("/players/%s?app_id=%s", playerID, s.client.appID)
 	u, err := url.Parse(path)
@@ -231,11 +283,11 @@ func (s *PlayersService) Get(playerID string, opt ...PlayerGetOptions) (*Player,
 	q := u.Query()
 	q.Set("app_id", s.client.appID)
 	if len(opt) > 0 {
-		q.Set("email_auth_hash", opt[0].EmailAuthHash)
+		q.Set("email_auth_hash", s.client.emailAuthHash(opt[0]))
 	}
 	u.RawQuery = q.Encode()
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -255,6 +307,11 @@ func (s *PlayersService) Get(playerID string, opt ...PlayerGetOptions) (*Player,
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/players-add-a-device
 func (s *PlayersService) Create(player PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
+	return s.CreateContext(context.Background(), player)
+}
+
+// CreateContext creates a player with the provided context.
+func (s *PlayersService) CreateContext(ctx context.Context, player PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
 	// build the URL
 	u, err := url.Parse("/players")
 	if err != nil {
@@ -262,7 +319,8 @@ func (s *PlayersService) Create(player PlayerRequest) (*PlayerCreateResponse, *h
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), player)
+	s.client.signPlayerRequest(&player)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), player)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -276,11 +334,56 @@ func (s *PlayersService) Create(player PlayerRequest) (*PlayerCreateResponse, *h
 	return plResp, resp, err
 }
 
+// CreateEmail creates an email subscription. The fields of player are optional,
+// its device type and identifier are set from the email.
+// The identifier auth hash is computed when identity verification is enabled with SetIdentityVerification.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/create-email-device
+func (s *PlayersService) CreateEmail(email string, player PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
+	return s.CreateEmailContext(context.Background(), email, player)
+}
+
+// CreateEmailContext creates an email subscription with the provided context.
+func (s *PlayersService) CreateEmailContext(ctx context.Context, email string, player PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, nil, ValidationErrors{{Field: "identifier", Message: fmt.Sprintf("invalid email address %q", email)}}
+	}
+
+	player.DeviceType = DeviceTypeEmail
+	player.Identifier = email
+	return s.CreateContext(ctx, player)
+}
+
+// CreateSMS creates a SMS subscription for a phone number in E.164 format. The fields of player are optional,
+// its device type and identifier are set from the phone number.
+// The identifier auth hash is computed when identity verification is enabled with SetIdentityVerification.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/create-sms-device
+func (s *PlayersService) CreateSMS(phoneNumber string, player PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
+	return s.CreateSMSContext(context.Background(), phoneNumber, player)
+}
+
+// CreateSMSContext creates a SMS subscription with the provided context.
+func (s *PlayersService) CreateSMSContext(ctx context.Context, phoneNumber string, player PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
+	if !e164Pattern.MatchString(phoneNumber) {
+		return nil, nil, ValidationErrors{{Field: "identifier", Message: fmt.Sprintf("invalid E.164 phone number %q", phoneNumber)}}
+	}
+
+	player.DeviceType = DeviceTypeSMS
+	player.Identifier = phoneNumber
+	return s.CreateContext(ctx, player)
+}
+
 // Generate a link to download a CSV list of all the players.
 //
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/players_csv_export
 func (s *PlayersService) CSVExport(opt ...PlayerCSVExportOptions) (*PlayerCSVExportResponse, *http.Response, error) {
+	return s.CSVExportContext(context.Background(), opt...)
+}
+
+// CSVExportContext generates a link to download a CSV list of all the players with the provided context.
+func (s *PlayersService) CSVExportContext(ctx context.Context, opt ...PlayerCSVExportOptions) (*PlayerCSVExportResponse, *http.Response, error) {
 	// build the URL with the query string
 	u, err := url.Parse("/players/csv_export")
 	if err != nil {
@@ -295,7 +398,7 @@ func (s *PlayersService) CSVExport(opt ...PlayerCSVExportOptions) (*PlayerCSVExp
 	if len(opt) > 0 {
 		op = &opt[0]
 	}
-	req, err := s.client.NewRequest("POST", u.String(), op)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), op)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -313,6 +416,11 @@ func (s *PlayersService) CSVExport(opt ...PlayerCSVExportOptions) (*PlayerCSVExp
 //
 // OneSignal API docs: https://documentation.onesignal.com/reference/edit-device
 func (s *PlayersService) Update(playerID string, player PlayerRequest) (*SuccessResponse, *http.Response, error) {
+	return s.UpdateContext(context.Background(), playerID, player)
+}
+
+// UpdateContext updates a player with the provided context.
+func (s *PlayersService) UpdateContext(ctx context.Context, playerID string, player PlayerRequest) (*SuccessResponse, *http.Response, error) {
 	// build the URL
 	path := fmt.Sprintf("/players/%s", playerID)
 	u, err := url.Parse(path)
@@ -321,7 +429,8 @@ func (s *PlayersService) Update(playerID string, player PlayerRequest) (*Success
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("PUT", u.String(), player)
+	s.client.signPlayerRequest(&player)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", u.String(), player)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -339,6 +448,11 @@ func (s *PlayersService) Update(playerID string, player PlayerRequest) (*Success
 //
 // OneSignal API docs: https://documentation.onesignal.com/reference/edit-tags-with-external-user-id
 func (s *PlayersService) UpdateTagsWithExternalUserID(ExternalUserID string, opt UpdateTagsWithExternalUserIDOptions) (*SuccessResponse, *http.Response, error) {
+	return s.UpdateTagsWithExternalUserIDContext(context.Background(), ExternalUserID, opt)
+}
+
+// UpdateTagsWithExternalUserIDContext updates an existing device's tags using the External User ID with the provided context.
+func (s *PlayersService) UpdateTagsWithExternalUserIDContext(ctx context.Context, ExternalUserID string, opt UpdateTagsWithExternalUserIDOptions) (*SuccessResponse, *http.Response, error) {
 	// build the URL
 	path := fmt.Sprintf("/apps/%s/users/%s", s.client.appID, ExternalUserID)
 	u, err := url.Parse(path)
@@ -347,7 +461,96 @@ func (s *PlayersService) UpdateTagsWithExternalUserID(ExternalUserID string, opt
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("PUT", u.String(), opt)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", u.String(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plResp := &SuccessResponse{}
+	resp, err := s.client.Do(req, plResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return plResp, resp, err
+}
+
+// Delete a player.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/delete-user-record
+func (s *PlayersService) Delete(playerID string) (*SuccessResponse, *http.Response, error) {
+	return s.DeleteContext(context.Background(), playerID)
+}
+
+// DeleteContext deletes a player with the provided context.
+func (s *PlayersService) DeleteContext(ctx context.Context, playerID string) (*SuccessResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/players/%s?app_id=%s", playerID, s.client.appID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plResp := &SuccessResponse{}
+	resp, err := s.client.Do(req, plResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return plResp, resp, err
+}
+
+// Update a player's session, incrementing its session count.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/new-session
+func (s *PlayersService) OnSession(playerID string, opt PlayerOnSessionOptions) (*SuccessResponse, *http.Response, error) {
+	return s.OnSessionContext(context.Background(), playerID, opt)
+}
+
+// OnSessionContext updates a player's session with the provided context.
+func (s *PlayersService) OnSessionContext(ctx context.Context, playerID string, opt PlayerOnSessionOptions) (*SuccessResponse, *http.Response, error) {
+	return s.post(ctx, fmt.Sprintf("/players/%s/on_session", playerID), opt)
+}
+
+// Track a new purchase of a player.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/new-purchase
+func (s *PlayersService) OnPurchase(playerID string, opt PlayerOnPurchaseOptions) (*SuccessResponse, *http.Response, error) {
+	return s.OnPurchaseContext(context.Background(), playerID, opt)
+}
+
+// OnPurchaseContext tracks a new purchase of a player with the provided context.
+func (s *PlayersService) OnPurchaseContext(ctx context.Context, playerID string, opt PlayerOnPurchaseOptions) (*SuccessResponse, *http.Response, error) {
+	return s.post(ctx, fmt.Sprintf("/players/%s/on_purchase", playerID), opt)
+}
+
+// Increment the total session length of a player.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/increment-session-length
+func (s *PlayersService) OnFocus(playerID string, opt PlayerOnFocusOptions) (*SuccessResponse, *http.Response, error) {
+	return s.OnFocusContext(context.Background(), playerID, opt)
+}
+
+// OnFocusContext increments the total session length of a player with the provided context.
+func (s *PlayersService) OnFocusContext(ctx context.Context, playerID string, opt PlayerOnFocusOptions) (*SuccessResponse, *http.Response, error) {
+	return s.post(ctx, fmt.Sprintf("/players/%s/on_focus", playerID), opt)
+}
+
+// post sends the body to a player endpoint that returns a success flag
+func (s *PlayersService) post(ctx context.Context, path string, body interface{}) (*SuccessResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), body)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,6 +1,7 @@
 package onesignal
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -134,9 +135,9 @@ func TestPlayersService_List_returnsError(t *testing.T) {
 	})
 
 	_, resp, err := client.Players.List(opt)
-	errResp, ok := err.(*ErrorResponse)
-	if !ok {
-		t.Errorf("Error should be of type ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("Error should wrap an ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
 	}
 
 	want := "Invalid or missing authentication token"
@@ -296,3 +297,230 @@ func TestPlayersService_Update(t *testing.T) {
 		t.Errorf("Request has not been sent")
 	}
 }
+
+func TestPlayersService_Delete(t *testing.T) {
+	requestSent := false
+
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/players/fake-id", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "DELETE")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+
+		if got, want := r.URL.Query().Get("app_id"), client.appID; got != want {
+			t.Errorf("app_id: %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, testhelper.LoadFixture(t, "success-response.json"))
+	})
+
+	deleteRes, _, err := client.Players.Delete("fake-id")
+	if err != nil {
+		t.Errorf("Delete returned an error: %v", err)
+	}
+
+	want := &SuccessResponse{Success: true}
+	if !reflect.DeepEqual(deleteRes, want) {
+		t.Errorf("Request response: %+v, want %+v", deleteRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestPlayersService_Delete_returnsError(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/players/fake-id", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors":["Player not found"]}`)
+	})
+
+	_, _, err := client.Players.Delete("fake-id")
+	if !IsNotFound(err) {
+		t.Errorf("Delete should return a not found error, got %v", err)
+	}
+}
+
+func TestPlayersService_OnSession(t *testing.T) {
+	requestSent := false
+
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	opt := PlayerOnSessionOptions{
+		Language:    "en",
+		Timezone:    -28800,
+		GameVersion: "1.1",
+		Tags:        map[string]string{"level": "10"},
+	}
+
+	mux.HandleFunc("/players/fake-id/on_session", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+		testBody(t, r, &PlayerOnSessionOptions{}, &opt)
+
+		fmt.Fprint(w, testhelper.LoadFixture(t, "success-response.json"))
+	})
+
+	res, _, err := client.Players.OnSession("fake-id", opt)
+	if err != nil {
+		t.Errorf("OnSession returned an error: %v", err)
+	}
+
+	if want := (&SuccessResponse{Success: true}); !reflect.DeepEqual(res, want) {
+		t.Errorf("Request response: %+v, want %+v", res, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestPlayersService_OnPurchase(t *testing.T) {
+	requestSent := false
+
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	opt := PlayerOnPurchaseOptions{
+		Purchases: []Purchase{
+			{SKU: "foosku1", Amount: 1.99, ISO: "USD"},
+			{SKU: "foosku2", Amount: 4.99, ISO: "USD"},
+		},
+	}
+
+	mux.HandleFunc("/players/fake-id/on_purchase", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+		testBody(t, r, &PlayerOnPurchaseOptions{}, &opt)
+
+		fmt.Fprint(w, testhelper.LoadFixture(t, "success-response.json"))
+	})
+
+	res, _, err := client.Players.OnPurchase("fake-id", opt)
+	if err != nil {
+		t.Errorf("OnPurchase returned an error: %v", err)
+	}
+
+	if want := (&SuccessResponse{Success: true}); !reflect.DeepEqual(res, want) {
+		t.Errorf("Request response: %+v, want %+v", res, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestPlayersService_OnFocus(t *testing.T) {
+	requestSent := false
+
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	opt := PlayerOnFocusOptions{
+		State:      "ping",
+		ActiveTime: 60,
+	}
+
+	mux.HandleFunc("/players/fake-id/on_focus", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+		testBody(t, r, &PlayerOnFocusOptions{}, &opt)
+
+		fmt.Fprint(w, testhelper.LoadFixture(t, "success-response.json"))
+	})
+
+	res, _, err := client.Players.OnFocus("fake-id", opt)
+	if err != nil {
+		t.Errorf("OnFocus returned an error: %v", err)
+	}
+
+	if want := (&SuccessResponse{Success: true}); !reflect.DeepEqual(res, want) {
+		t.Errorf("Request response: %+v, want %+v", res, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestPlayersService_CreateEmail(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, &PlayerRequest{}, &PlayerRequest{
+			DeviceType:     DeviceTypeEmail,
+			Identifier:     "foo@example.com",
+			ExternalUserID: "user-id",
+		})
+
+		fmt.Fprint(w, `{"success": true, "id": "player-fake-id"}`)
+	})
+
+	res, _, err := client.Players.CreateEmail("foo@example.com", PlayerRequest{ExternalUserID: "user-id"})
+	if err != nil {
+		t.Errorf("CreateEmail returned an error: %v", err)
+	}
+	if want := (&PlayerCreateResponse{Success: true, ID: "player-fake-id"}); !reflect.DeepEqual(res, want) {
+		t.Errorf("CreateEmail returned %+v, want %+v", res, want)
+	}
+}
+
+func TestPlayersService_CreateSMS(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetIdentityVerification(true)
+
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, &PlayerRequest{}, &PlayerRequest{
+			DeviceType:         DeviceTypeSMS,
+			Identifier:         "+15555550100",
+			IdentifierAuthHash: IdentityHash(client.apiKey, "+15555550100"),
+		})
+
+		fmt.Fprint(w, `{"success": true, "id": "player-fake-id"}`)
+	})
+
+	if _, _, err := client.Players.CreateSMS("+15555550100", PlayerRequest{}); err != nil {
+		t.Errorf("CreateSMS returned an error: %v", err)
+	}
+}
+
+func TestPlayersService_CreateEmailSMS_invalid(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request should not have been sent")
+	})
+
+	for _, email := range []string{"", "foo", "Foo <foo@example.com>"} {
+		_, _, err := client.Players.CreateEmail(email, PlayerRequest{})
+		if got, want := validationFields(t, err), []string{"identifier"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CreateEmail(%q) validation errors on %v, want %v", email, got, want)
+		}
+	}
+
+	for _, number := range []string{"", "5555550100", "+0555550100", "+1 555 555 0100"} {
+		_, _, err := client.Players.CreateSMS(number, PlayerRequest{})
+		if got, want := validationFields(t, err), []string{"identifier"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CreateSMS(%q) validation errors on %v, want %v", number, got, want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EndpointClass groups the API endpoints sharing a rate limit bucket.
+type EndpointClass string
+
+const (
+	// All endpoints without a dedicated class.
+	EndpointClassDefault EndpointClass = "default"
+	// POST /notifications
+	EndpointClassNotificationsCreate EndpointClass = "notifications.create"
+	// PUT /players/{id} and PUT /apps/{app_id}/users/{external_user_id}
+	EndpointClassPlayersUpdate EndpointClass = "players.update"
+)
+
+// ErrRateLimited is returned when a fail fast rate limiter rejects a request.
+var ErrRateLimited = errors.New("onesignal: client side rate limit exceeded")
+
+// RateLimiter controls the rate of requests sent to the OneSignal API.
+// Implementations must be safe for concurrent use since a client can be shared by many goroutines.
+type RateLimiter interface {
+	// Wait blocks until a request of the endpoint class can be sent with the API key,
+	// or returns an error if the request must not be sent.
+	Wait(ctx context.Context, apiKey string, class EndpointClass) error
+	// Observe adapts the limiter from the status code and rate limit headers of an API response.
+	Observe(apiKey string, class EndpointClass, statusCode int, rl RateLimit)
+}
+
+// SetRateLimiter set the rate limiter applied to every request. A nil limiter disables rate limiting.
+func (c *httpClient) SetRateLimiter(limiter RateLimiter) {
+	c.rateLimiter = limiter
+}
+
+// endpointClassOf returns the endpoint class of the request
+func (c *httpClient) endpointClassOf(r *http.Request) EndpointClass {
+	switch c.routeOf(r) {
+	case OperationNotificationsCreate:
+		return EndpointClassNotificationsCreate
+	case OperationPlayersUpdate, OperationPlayersUpdateTags:
+		return EndpointClassPlayersUpdate
+	default:
+		return EndpointClassDefault
+	}
+}
+
+// Rate describes the sustained rate and the burst size of a token bucket.
+type Rate struct {
+	// Number of requests allowed per second. Zero means unlimited.
+	PerSecond float64
+	// Maximum number of requests sent at once. Defaults to 1.
+	Burst int
+}
+
+// TokenBucketOptions specifies the parameters of a TokenBucketLimiter
+type TokenBucketOptions struct {
+	// Rate of the endpoint classes that aren't listed in Classes
+	Default Rate
+	// Rate of each endpoint class
+	Classes map[EndpointClass]Rate
+	// Return ErrRateLimited instead of waiting when no token is available
+	FailFast bool
+	// Delay applied after a 429 response without Retry-After and X-RateLimit-Reset headers.
+	// Defaults to 1 second.
+	ThrottleDelay time.Duration
+}
+
+// TokenBucketLimiter is a RateLimiter keeping a token bucket per API key and endpoint class.
+// Buckets are paused when OneSignal reports that the rate limit is exhausted.
+type TokenBucketLimiter struct {
+	opts    TokenBucketOptions
+	now     func() time.Time
+	mu      sync.Mutex
+	buckets map[bucketKey]*tokenBucket
+}
+
+type bucketKey struct {
+	apiKey string
+	class  EndpointClass
+}
+
+type tokenBucket struct {
+	rate         Rate
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// NewTokenBucketLimiter returns a new TokenBucketLimiter
+func NewTokenBucketLimiter(opts TokenBucketOptions) *TokenBucketLimiter {
+	if opts.ThrottleDelay <= 0 {
+		opts.ThrottleDelay = time.Second
+	}
+	return &TokenBucketLimiter{
+		opts:    opts,
+		now:     time.Now,
+		buckets: make(map[bucketKey]*tokenBucket),
+	}
+}
+
+// bucket returns the bucket of the key, creating it if needed. The lock must be held.
+func (l *TokenBucketLimiter) bucket(key bucketKey, now time.Time) *tokenBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		rate, ok := l.opts.Classes[key.class]
+		if !ok {
+			rate = l.opts.Default
+		}
+		if rate.Burst < 1 {
+			rate.Burst = 1
+		}
+		b = &tokenBucket{
+			rate:   rate,
+			tokens: float64(rate.Burst),
+			last:   now,
+		}
+		l.buckets[key] = b
+	}
+
+	return b
+}
+
+// reserve takes a token and returns the delay before it can be used
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	var wait time.Duration
+	if b.blockedUntil.After(now) {
+		wait = b.blockedUntil.Sub(now)
+	}
+	if b.rate.PerSecond <= 0 {
+		return wait
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate.PerSecond
+		if max := float64(b.rate.Burst); b.tokens > max {
+			b.tokens = max
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens < 0 {
+		if d := time.Duration(-b.tokens / b.rate.PerSecond * float64(time.Second)); d > wait {
+			wait = d
+		}
+	}
+
+	return wait
+}
+
+// cancel gives back a token taken by reserve
+func (b *tokenBucket) cancel() {
+	if b.rate.PerSecond > 0 {
+		b.tokens++
+	}
+}
+
+// Wait implements RateLimiter
+func (l *TokenBucketLimiter) Wait(ctx context.Context, apiKey string, class EndpointClass) error {
+	now := l.now()
+
+	l.mu.Lock()
+	b := l.bucket(bucketKey{apiKey, class}, now)
+	wait := b.reserve(now)
+	if wait > 0 && l.opts.FailFast {
+		b.cancel()
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := sleepContext(ctx, wait); err != nil {
+		l.mu.Lock()
+		b.cancel()
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Observe implements RateLimiter
+func (l *TokenBucketLimiter) Observe(apiKey string, class EndpointClass, statusCode int, rl RateLimit) {
+	now := l.now()
+
+	var until time.Time
+	switch {
+	case statusCode == http.StatusTooManyRequests && rl.RetryAfter > 0:
+		until = now.Add(rl.RetryAfter)
+	case rl.Remaining == 0 && rl.Reset.After(now):
+		until = rl.Reset
+	case statusCode == http.StatusTooManyRequests:
+		until = now.Add(l.opts.ThrottleDelay)
+	default:
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(bucketKey{apiKey, class}, now)
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+	if b.tokens > 0 {
+		b.tokens = 0
+		b.last = now
+	}
+}
@@ -0,0 +1,166 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiter_failFast(t *testing.T) {
+	limiter := NewTokenBucketLimiter(TokenBucketOptions{
+		Default:  Rate{PerSecond: 1, Burst: 2},
+		FailFast: true,
+	})
+	now := time.Date(2021, time.June, 1, 10, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != nil {
+			t.Fatalf("Wait %d returned unexpected error: %v", i, err)
+		}
+	}
+
+	if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != ErrRateLimited {
+		t.Errorf("Wait error is %v, want %v", err, ErrRateLimited)
+	}
+
+	// buckets are separated by api key and endpoint class
+	if err := limiter.Wait(ctx, "other-key", EndpointClassDefault); err != nil {
+		t.Errorf("Wait with another api key returned unexpected error: %v", err)
+	}
+	if err := limiter.Wait(ctx, "key", EndpointClassNotificationsCreate); err != nil {
+		t.Errorf("Wait with another endpoint class returned unexpected error: %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != nil {
+		t.Errorf("Wait after refill returned unexpected error: %v", err)
+	}
+}
+
+func TestTokenBucketLimiter_wait(t *testing.T) {
+	limiter := NewTokenBucketLimiter(TokenBucketOptions{
+		Classes: map[EndpointClass]Rate{
+			EndpointClassNotificationsCreate: {PerSecond: 50, Burst: 1},
+		},
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx, "key", EndpointClassNotificationsCreate); err != nil {
+			t.Fatalf("Wait returned unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Wait elapsed %v, want at least %v", elapsed, 30*time.Millisecond)
+	}
+
+	// the default rate is unlimited
+	for i := 0; i < 100; i++ {
+		if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != nil {
+			t.Fatalf("Wait returned unexpected error: %v", err)
+		}
+	}
+
+	limiter.Observe("key", EndpointClassDefault, http.StatusTooManyRequests, RateLimit{RetryAfter: time.Hour})
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != context.DeadlineExceeded {
+		t.Errorf("Wait error is %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTokenBucketLimiter_observe(t *testing.T) {
+	limiter := NewTokenBucketLimiter(TokenBucketOptions{FailFast: true})
+	now := time.Date(2021, time.June, 1, 10, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	limiter.Observe("key", EndpointClassDefault, http.StatusOK, RateLimit{Limit: 10, Remaining: 0, Reset: now.Add(time.Minute)})
+	if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != ErrRateLimited {
+		t.Errorf("Wait error is %v, want %v", err, ErrRateLimited)
+	}
+
+	now = now.Add(time.Minute)
+	if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != nil {
+		t.Errorf("Wait after reset returned unexpected error: %v", err)
+	}
+
+	limiter.Observe("key", EndpointClassDefault, http.StatusTooManyRequests, RateLimit{Remaining: -1})
+	if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != ErrRateLimited {
+		t.Errorf("Wait error is %v, want %v", err, ErrRateLimited)
+	}
+
+	now = now.Add(time.Second)
+	if err := limiter.Wait(ctx, "key", EndpointClassDefault); err != nil {
+		t.Errorf("Wait after throttle delay returned unexpected error: %v", err)
+	}
+}
+
+func TestDo_rateLimiter(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRateLimiter(NewTokenBucketLimiter(TokenBucketOptions{
+		Classes: map[EndpointClass]Rate{
+			EndpointClassNotificationsCreate: {PerSecond: 0.001, Burst: 1},
+		},
+		FailFast: true,
+	}))
+
+	requests := 0
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"id":"notif-fake-id","recipients":1}`)
+	})
+
+	if _, _, err := client.Notifications.Create(sampleNotificationRequest); err != nil {
+		t.Fatalf("Create returned unexpected error: %v", err)
+	}
+
+	_, _, err := client.Notifications.Create(sampleNotificationRequest)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Create error is %v, want %v", err, ErrRateLimited)
+	}
+	if !IsRateLimited(err) {
+		t.Errorf("IsRateLimited(%v) should be true", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Requests: %d, want %d", requests, 1)
+	}
+
+	// other endpoints use other buckets
+	if _, _, err := client.Notifications.List(); err != nil {
+		t.Errorf("List returned unexpected error: %v", err)
+	}
+}
+
+func TestEndpointClassOf(t *testing.T) {
+	client, _ := NewClient("app-id", "api-key")
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		want   EndpointClass
+	}{
+		{"POST", "/api/v1/notifications", EndpointClassNotificationsCreate},
+		{"GET", "/api/v1/notifications", EndpointClassDefault},
+		{"PUT", "/api/v1/players/id123", EndpointClassPlayersUpdate},
+		{"PUT", "/api/v1/apps/app-id/users/user-id", EndpointClassPlayersUpdate},
+		{"POST", "/api/v1/players", EndpointClassDefault},
+		{"POST", "/api/v1/apps/app-id/live_activities/activity-id/notifications", EndpointClassDefault},
+		{"PUT", "/api/v1/players/id123/unknown", EndpointClassDefault},
+	} {
+		r, _ := http.NewRequest(tc.method, "https://onesignal.com"+tc.path, nil)
+		if got := client.endpointClassOf(r); got != tc.want {
+			t.Errorf("endpointClassOf(%s %s) = %v, want %v", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// IdempotencyKeyHeader is the request header that marks a request as safe to retry
+// even if its HTTP method is not idempotent.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy describes how failed requests are retried by the client.
+// Only requests with an idempotent method (GET, HEAD, OPTIONS, PUT, DELETE)
+// or carrying an Idempotency-Key header are retried. Track open requests are never retried
+// since each of them counts an open of the notification.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one. Values lower than 2 disable retries.
+	MaxAttempts int
+	// Backoff before the first retry. It is doubled on each subsequent retry.
+	MinBackoff time.Duration
+	// Upper bound of the computed backoff. It doesn't apply to Retry-After headers.
+	MaxBackoff time.Duration
+	// Fraction of the backoff, between 0 and 1, that is randomized to spread retries.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns a policy with 3 attempts and exponential backoff
+// from 500ms up to 30s with 20% jitter.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// SetRetryPolicy set the retry policy used by Do. A nil policy disables retries.
+func (c *httpClient) SetRetryPolicy(policy *RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// canRetry reports whether the request of the operation op may be sent again
+func (p *RetryPolicy) canRetry(r *http.Request, op Operation) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	case http.MethodPut:
+		// each track open request counts an open of the notification
+		return op != OperationNotificationsTrackOpen
+	default:
+		return r.Header.Get(IdempotencyKeyHeader) != ""
+	}
+}
+
+// backoff returns the delay before the next attempt.
+// The Retry-After header of the response takes precedence when present.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+
+	d := float64(p.MinBackoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// shouldRetry reports whether the outcome of an attempt is transient
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses the Retry-After header value,
+// either delay seconds or a HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
+
+// sendWithRetry sends the request and retries transient failures
+// according to the retry policy of the client
+func (c *httpClient) sendWithRetry(r *http.Request) (*http.Response, error) {
+	policy := c.retryPolicy
+	if !policy.canRetry(r, c.routeOf(r)) {
+		return c.sendAttempt(r, 1)
+	}
+
+	req := r
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendAttempt(req, attempt)
+		if errors.Is(err, ErrRateLimited) {
+			return resp, err
+		}
+		if attempt >= policy.MaxAttempts || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait := policy.backoff(attempt, resp)
+		fields := []interface{}{"method", r.Method, "path", r.URL.Path, "attempt", attempt + 1, "max_attempts", policy.MaxAttempts, "wait", wait}
+		if err != nil {
+			fields = append(fields, "error", err)
+		} else {
+			fields = append(fields, "status", resp.StatusCode)
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		c.log(r.Context(), LogLevelWarn, "retrying request", fields...)
+
+		if err := sleepContext(r.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		req = r.Clone(r.Context())
+		if r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// sendAttempt sends the request once and logs the response
+func (c *httpClient) sendAttempt(r *http.Request, attempt int) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.sendOnce(r)
+	recordAttempt(r, resp, err)
+	if c.logger != nil {
+		fields := []interface{}{"method", r.Method, "path", r.URL.Path, "attempt", attempt, "latency", time.Since(start)}
+		if err != nil {
+			c.log(r.Context(), LogLevelDebug, "response", append(fields, "error", err)...)
+		} else {
+			c.log(r.Context(), LogLevelDebug, "response", append(fields, "status", resp.StatusCode)...)
+		}
+	}
+	return resp, err
+}
+
+// sendOnce sends the request once, within the limits of the rate limiter of the client
+func (c *httpClient) sendOnce(r *http.Request) (*http.Response, error) {
+	if c.rateLimiter == nil {
+		return c.client.Do(r)
+	}
+
+	class := c.endpointClassOf(r)
+	if err := c.rateLimiter.Wait(r.Context(), c.apiKey, class); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(r)
+	if err == nil {
+		c.rateLimiter.Observe(c.apiKey, class, resp.StatusCode, parseRateLimit(resp.Header))
+	}
+
+	return resp, err
+}
+
+// sleepContext pauses the current goroutine for at least the duration d
+// or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,227 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+func TestDo_retryTransientErrors(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"success":true}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	body := &SuccessResponse{}
+	_, err := client.Do(req, body)
+	if err != nil {
+		t.Fatalf("Do returned unexpected error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Attempts: %d, want %d", attempts, 3)
+	}
+
+	if !body.Success {
+		t.Errorf("Response body should be decoded from the last attempt")
+	}
+}
+
+func TestDo_retryGivesUp(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"errors":["API rate limit exceeded"]}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	resp, err := client.Do(req, nil)
+	if err == nil {
+		t.Fatalf("Do should return an error")
+	}
+
+	if attempts != 3 {
+		t.Errorf("Attempts: %d, want %d", attempts, 3)
+	}
+
+	if got, want := resp.StatusCode, http.StatusTooManyRequests; got != want {
+		t.Errorf("Status code: %d, want %d", got, want)
+	}
+}
+
+func TestDo_retryNonIdempotentRequest(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequest("POST", "/", struct{ Foo string }{Foo: "Bar"})
+	client.Do(req, nil)
+	if attempts != 1 {
+		t.Errorf("Attempts without idempotency key: %d, want %d", attempts, 1)
+	}
+
+	attempts = 0
+	req, _ = client.NewRequest("POST", "/", struct{ Foo string }{Foo: "Bar"})
+	req.Header.Set(IdempotencyKeyHeader, "fake-key")
+	client.Do(req, nil)
+	if attempts != 3 {
+		t.Errorf("Attempts with idempotency key: %d, want %d", attempts, 3)
+	}
+}
+
+func TestDo_retryTrackOpen(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/notifications/notif-id", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	if _, _, err := client.Notifications.TrackOpen("notif-id", "player-id"); err == nil {
+		t.Fatalf("TrackOpen should return an error")
+	}
+	if attempts != 1 {
+		t.Errorf("Attempts: %d, want %d", attempts, 1)
+	}
+}
+
+func TestDo_retryResendsBody(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	type foo struct{ Foo string }
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		testBody(t, r, &foo{}, &foo{Foo: "Bar"})
+		if attempts < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := client.NewRequest("PUT", "/", foo{Foo: "Bar"})
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned unexpected error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Attempts: %d, want %d", attempts, 2)
+	}
+}
+
+func TestDo_retryContextCanceled(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequestWithContext(ctx, "GET", "/", nil)
+	_, err := client.Do(req, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Do error is %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  100 * time.Millisecond,
+		MaxBackoff:  300 * time.Millisecond,
+	}
+
+	for attempt, want := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		300 * time.Millisecond,
+		300 * time.Millisecond,
+	} {
+		if got := policy.backoff(attempt+1, nil); got != want {
+			t.Errorf("Backoff of attempt %d: %v, want %v", attempt+1, got, want)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "2")
+	if got, want := policy.backoff(1, resp), 2*time.Second; got != want {
+		t.Errorf("Backoff with Retry-After: %v, want %v", got, want)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		got := policy.backoff(1, nil)
+		if got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Errorf("Backoff with jitter: %v, want between %v and %v", got, 50*time.Millisecond, 100*time.Millisecond)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Tue, 01 Jun 2021 10:00:30 GMT", 30 * time.Second, true},
+		{"Tue, 01 Jun 2021 09:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		got, ok := parseRetryAfter(tc.value, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SegmentsService handles communication with the segment related
+// methods of the OneSignal API.
+type SegmentsService struct {
+	client *Client
+}
+
+// SegmentRequest represents a request to create a segment.
+type SegmentRequest struct {
+	// Name of the segment, as referenced by included_segments and excluded_segments.
+	// It's not unique, segments with the same name are all targeted.
+	Name string `json:"name"`
+	// Filters of the users of the segment, like the filters of a notification.
+	Filters Filters `json:"filters"`
+}
+
+// SegmentCreateResponse wraps the standard http.Response for the
+// SegmentsService.Create method
+type SegmentCreateResponse struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id"`
+}
+
+// Validate checks that the segment has a name and valid filters.
+// It returns ValidationErrors listing every violated rule, or nil.
+func (r *SegmentRequest) Validate() error {
+	var errs ValidationErrors
+	if r.Name == "" {
+		errs.add("name", "is required")
+	}
+	if len(r.Filters) == 0 {
+		errs.add("filters", "is required")
+	} else if err := r.Filters.Validate(); err != nil {
+		errs.add("filters", "%v", err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Create a segment.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/create-segments
+func (s *SegmentsService) Create(opt *SegmentRequest) (*SegmentCreateResponse, *http.Response, error) {
+	return s.CreateContext(context.Background(), opt)
+}
+
+// CreateContext creates a segment with the provided context.
+func (s *SegmentsService) CreateContext(ctx context.Context, opt *SegmentRequest) (*SegmentCreateResponse, *http.Response, error) {
+	if s.client.validateRequests {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/apps/%s/segments", s.client.appID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createRes := &SegmentCreateResponse{}
+	resp, err := s.client.Do(req, createRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return createRes, resp, err
+}
+
+// Delete a segment.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/delete-segments
+func (s *SegmentsService) Delete(segmentID string) (*SuccessResponse, *http.Response, error) {
+	return s.DeleteContext(context.Background(), segmentID)
+}
+
+// DeleteContext deletes a segment with the provided context.
+func (s *SegmentsService) DeleteContext(ctx context.Context, segmentID string) (*SuccessResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/apps/%s/segments/%s", s.client.appID, segmentID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deleteRes := &SuccessResponse{}
+	resp, err := s.client.Do(req, deleteRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return deleteRes, resp, err
+}
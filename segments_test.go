@@ -0,0 +1,104 @@
+package onesignal
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSegmentsService_Create(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	requestSent := false
+
+	segmentRequest := &SegmentRequest{
+		Name: "Big spenders",
+		Filters: Filters{
+			FilterAmountSpent(FilterRelationGreaterThan, 100),
+			FilterOr(),
+			FilterTag("vip", FilterRelationEqual, "true"),
+		},
+	}
+
+	mux.HandleFunc("/apps/fake-app-id/segments", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+		testBody(t, r, &SegmentRequest{}, segmentRequest)
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"id": "segment-fake-id"
+		}`)
+	})
+
+	createRes, _, err := client.Segments.Create(segmentRequest)
+	if err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+
+	want := &SegmentCreateResponse{Success: true, ID: "segment-fake-id"}
+	if !reflect.DeepEqual(createRes, want) {
+		t.Errorf("Create returned %+v, want %+v", createRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestSegmentsService_Create_validation(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRequestValidation(true)
+
+	requestSent := false
+	mux.HandleFunc("/apps/fake-app-id/segments", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Segments.Create(&SegmentRequest{
+		Filters: Filters{FilterOr()},
+	})
+	if got, want := validationFields(t, err), []string{"name", "filters"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Create validation errors on %v, want %v", got, want)
+	}
+
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
+
+func TestSegmentsService_Delete(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	requestSent := false
+
+	mux.HandleFunc("/apps/fake-app-id/segments/segment-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "DELETE")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	deleteRes, _, err := client.Segments.Delete("segment-fake-id")
+	if err != nil {
+		t.Errorf("Delete returned an error: %v", err)
+	}
+
+	want := &SuccessResponse{Success: true}
+	if !reflect.DeepEqual(deleteRes, want) {
+		t.Errorf("Delete returned %+v, want %+v", deleteRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
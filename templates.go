@@ -0,0 +1,280 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// TemplatesService handles communication with the template related
+// methods of the OneSignal API.
+type TemplatesService struct {
+	client *Client
+}
+
+// Template represents a OneSignal template.
+type Template struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Channel   MessageType `json:"channel,omitempty"`
+	CreatedAt string      `json:"created_at,omitempty"`
+	UpdatedAt string      `json:"updated_at,omitempty"`
+	// Content of the template, returned by TemplatesService.Get
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// TemplateRequest represents a request to create or update a template.
+// The Name field is the name of the template, the other fields are the content fields of a notification;
+// targeting and scheduling fields are ignored.
+type TemplateRequest struct {
+	NotificationRequest
+	// Set to create an email template
+	IsEmail bool `json:"isEmail,omitempty"`
+	// Set to create a SMS template
+	IsSMS bool `json:"isSMS,omitempty"`
+}
+
+// TemplateListOptions specifies the parameters to the TemplatesService.List method
+type TemplateListOptions struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// Channel of the templates. Default (not set) is all channels.
+	Channel MessageType `json:"channel,omitempty"`
+}
+
+// TemplateListResponse wraps the standard http.Response for the
+// TemplatesService.List method
+type TemplateListResponse struct {
+	TotalCount int        `json:"total_count"`
+	Offset     int        `json:"offset"`
+	Limit      int        `json:"limit"`
+	Templates  []Template `json:"templates"`
+}
+
+// TemplateCreateResponse wraps the standard http.Response for the
+// TemplatesService.Create and TemplatesService.Copy methods
+type TemplateCreateResponse struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id"`
+}
+
+// templateCopyRequest is the body of the TemplatesService.Copy method
+type templateCopyRequest struct {
+	TargetAppID string `json:"target_app_id"`
+}
+
+// List the templates.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/view-templates
+func (s *TemplatesService) List(opt ...TemplateListOptions) (*TemplateListResponse, *http.Response, error) {
+	return s.ListContext(context.Background(), opt...)
+}
+
+// ListContext lists the templates with the provided context.
+func (s *TemplatesService) ListContext(ctx context.Context, opt ...TemplateListOptions) (*TemplateListResponse, *http.Response, error) {
+	// build the URL with the query string
+	u, err := url.Parse("/templates")
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	q.Set("app_id", s.client.appID)
+	if len(opt) > 0 {
+		q.Set("limit", strconv.Itoa(opt[0].Limit))
+		q.Set("offset", strconv.Itoa(opt[0].Offset))
+		if opt[0].Channel != "" {
+			q.Set("channel", string(opt[0].Channel))
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlResp := &TemplateListResponse{}
+	resp, err := s.client.Do(req, tlResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return tlResp, resp, err
+}
+
+// Get a single template.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/view-template
+func (s *TemplatesService) Get(templateID string) (*Template, *http.Response, error) {
+	return s.GetContext(context.Background(), templateID)
+}
+
+// GetContext gets a single template with the provided context.
+func (s *TemplatesService) GetContext(ctx context.Context, templateID string) (*Template, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/templates/%s?app_id=%s", templateID, s.client.appID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &Template{}
+	resp, err := s.client.Do(req, template)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return template, resp, err
+}
+
+// Create a template.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/create-template
+func (s *TemplatesService) Create(opt *TemplateRequest) (*TemplateCreateResponse, *http.Response, error) {
+	return s.CreateContext(context.Background(), opt)
+}
+
+// CreateContext creates a template with the provided context.
+func (s *TemplatesService) CreateContext(ctx context.Context, opt *TemplateRequest) (*TemplateCreateResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse("/templates")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	opt.AppID = s.client.appID
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createRes := &TemplateCreateResponse{}
+	resp, err := s.client.Do(req, createRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return createRes, resp, err
+}
+
+// Update a template.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/update-template
+func (s *TemplatesService) Update(templateID string, opt *TemplateRequest) (*SuccessResponse, *http.Response, error) {
+	return s.UpdateContext(context.Background(), templateID, opt)
+}
+
+// UpdateContext updates a template with the provided context.
+func (s *TemplatesService) UpdateContext(ctx context.Context, templateID string, opt *TemplateRequest) (*SuccessResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/templates/%s?app_id=%s", templateID, s.client.appID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	opt.AppID = s.client.appID
+	req, err := s.client.NewRequestWithContext(ctx, "PATCH", u.String(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updateRes := &SuccessResponse{}
+	resp, err := s.client.Do(req, updateRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updateRes, resp, err
+}
+
+// Delete a template.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/delete-template
+func (s *TemplatesService) Delete(templateID string) (*SuccessResponse, *http.Response, error) {
+	return s.DeleteContext(context.Background(), templateID)
+}
+
+// DeleteContext deletes a template with the provided context.
+func (s *TemplatesService) DeleteContext(ctx context.Context, templateID string) (*SuccessResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/templates/%s?app_id=%s", templateID, s.client.appID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deleteRes := &SuccessResponse{}
+	resp, err := s.client.Do(req, deleteRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return deleteRes, resp, err
+}
+
+// Copy a template to another app.
+// This endpoint requires the user auth key: create the Client with NewClient(appID, userAuthKey).
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/copy-template-to-app
+func (s *TemplatesService) Copy(templateID, targetAppID string) (*TemplateCreateResponse, *http.Response, error) {
+	return s.CopyContext(context.Background(), templateID, targetAppID)
+}
+
+// CopyContext copies a template to another app with the provided context.
+func (s *TemplatesService) CopyContext(ctx context.Context, templateID, targetAppID string) (*TemplateCreateResponse, *http.Response, error) {
+	// build the URL
+	u, err := url.Parse(fmt.Sprintf("/templates/%s/copy_to_app?app_id=%s", templateID, s.client.appID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), &templateCopyRequest{TargetAppID: targetAppID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	copyRes := &TemplateCreateResponse{}
+	resp, err := s.client.Do(req, copyRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return copyRes, resp, err
+}
+
+// CreateFromTemplate creates a notification from a template, for the targets of opt.
+// The data is available in the template as {{ message.custom_data.<key> }}.
+// opt must set the targets: a nil opt returns ValidationErrors instead of sending to every subscriber.
+func (s *NotificationsService) CreateFromTemplate(templateID string, opt *NotificationRequest, data map[string]interface{}) (*NotificationCreateResponse, *http.Response, error) {
+	return s.CreateFromTemplateContext(context.Background(), templateID, opt, data)
+}
+
+// CreateFromTemplateContext creates a notification from a template with the provided context.
+func (s *NotificationsService) CreateFromTemplateContext(ctx context.Context, templateID string, opt *NotificationRequest, data map[string]interface{}) (*NotificationCreateResponse, *http.Response, error) {
+	if opt == nil {
+		return nil, nil, ValidationErrors{{Field: "included_segments", Message: "a target is required: included_segments, filters or include_* parameters"}}
+	}
+	req := *opt
+	req.TemplateID = templateID
+	if data != nil {
+		req.CustomData = data
+	}
+
+	return s.CreateContext(ctx, &req)
+}
@@ -0,0 +1,219 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestTemplatesService_List(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testHeader(t, r, "Authorization", "Basic "+client.apiKey)
+
+		want := url.Values{
+			"app_id":  {client.appID},
+			"limit":   {"10"},
+			"offset":  {"20"},
+			"channel": {"email"},
+		}
+		if got := r.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Query: %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, `{
+			"total_count": 21,
+			"offset": 20,
+			"limit": 10,
+			"templates": [
+				{"id": "template-fake-id", "name": "Welcome", "channel": "email", "created_at": "2021-01-19T23:15:43.000Z", "updated_at": "2021-01-20T10:00:00.000Z"}
+			]
+		}`)
+	})
+
+	res, _, err := client.Templates.List(TemplateListOptions{Limit: 10, Offset: 20, Channel: MessageTypeEmail})
+	if err != nil {
+		t.Errorf("List returned an error: %v", err)
+	}
+
+	want := &TemplateListResponse{
+		TotalCount: 21,
+		Offset:     20,
+		Limit:      10,
+		Templates: []Template{{
+			ID:        "template-fake-id",
+			Name:      "Welcome",
+			Channel:   MessageTypeEmail,
+			CreatedAt: "2021-01-19T23:15:43.000Z",
+			UpdatedAt: "2021-01-20T10:00:00.000Z",
+		}},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("List returned %+v, want %+v", res, want)
+	}
+}
+
+func TestTemplatesService_Get(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/templates/template-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("app_id"), client.appID; got != want {
+			t.Errorf("app_id: %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, `{
+			"id": "template-fake-id",
+			"name": "Welcome",
+			"channel": "push",
+			"content": {"en": "Welcome {{ message.custom_data.name }}"}
+		}`)
+	})
+
+	template, _, err := client.Templates.Get("template-fake-id")
+	if err != nil {
+		t.Errorf("Get returned an error: %v", err)
+	}
+
+	want := &Template{
+		ID:      "template-fake-id",
+		Name:    "Welcome",
+		Channel: MessageTypePush,
+		Content: map[string]interface{}{"en": "Welcome {{ message.custom_data.name }}"},
+	}
+	if !reflect.DeepEqual(template, want) {
+		t.Errorf("Get returned %+v, want %+v", template, want)
+	}
+}
+
+func TestTemplatesService_CreateUpdateDelete(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	templateRequest := &TemplateRequest{
+		NotificationRequest: NotificationRequest{
+			Name:         "Welcome",
+			EmailSubject: "Welcome",
+			EmailBody:    `Hello {{ message.custom_data.name }} <a href="[unsubscribe_url]">Unsubscribe</a>`,
+		},
+		IsEmail: true,
+	}
+	want := *templateRequest
+	want.AppID = "fake-app-id"
+
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, &TemplateRequest{}, &want)
+		fmt.Fprint(w, `{"success": true, "id": "template-fake-id"}`)
+	})
+	mux.HandleFunc("/templates/template-fake-id", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("app_id"), client.appID; got != want {
+			t.Errorf("app_id: %v, want %v", got, want)
+		}
+		switch r.Method {
+		case "PATCH":
+			testBody(t, r, &TemplateRequest{}, &want)
+		case "DELETE":
+		default:
+			t.Errorf("Unexpected method %v", r.Method)
+		}
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	createRes, _, err := client.Templates.Create(templateRequest)
+	if err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+	if want := (&TemplateCreateResponse{Success: true, ID: "template-fake-id"}); !reflect.DeepEqual(createRes, want) {
+		t.Errorf("Create returned %+v, want %+v", createRes, want)
+	}
+
+	if _, _, err := client.Templates.Update("template-fake-id", templateRequest); err != nil {
+		t.Errorf("Update returned an error: %v", err)
+	}
+
+	deleteRes, _, err := client.Templates.Delete("template-fake-id")
+	if err != nil {
+		t.Errorf("Delete returned an error: %v", err)
+	}
+	if !deleteRes.Success {
+		t.Errorf("Delete should succeed")
+	}
+}
+
+func TestTemplatesService_Copy(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/templates/template-fake-id/copy_to_app", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if got, want := r.URL.Query().Get("app_id"), client.appID; got != want {
+			t.Errorf("app_id: %v, want %v", got, want)
+		}
+		testBody(t, r, &templateCopyRequest{}, &templateCopyRequest{TargetAppID: "other-app-id"})
+
+		fmt.Fprint(w, `{"success": true, "id": "template-copy-id"}`)
+	})
+
+	copyRes, _, err := client.Templates.Copy("template-fake-id", "other-app-id")
+	if err != nil {
+		t.Errorf("Copy returned an error: %v", err)
+	}
+	if want := (&TemplateCreateResponse{Success: true, ID: "template-copy-id"}); !reflect.DeepEqual(copyRes, want) {
+		t.Errorf("Copy returned %+v, want %+v", copyRes, want)
+	}
+}
+
+func TestNotificationsService_CreateFromTemplate(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, &NotificationRequest{}, &NotificationRequest{
+			AppID:                     client.appID,
+			TemplateID:                "template-fake-id",
+			ChannelForExternalUserIDs: MessageTypeEmail,
+			IncludeExternalUserIDs:    []string{"user-id"},
+			CustomData:                map[string]interface{}{"name": "Jane"},
+		})
+		fmt.Fprint(w, `{"id": "notif-fake-id", "recipients": 1}`)
+	})
+
+	target := &NotificationRequest{
+		ChannelForExternalUserIDs: MessageTypeEmail,
+		IncludeExternalUserIDs:    []string{"user-id"},
+	}
+	client.SetRequestValidation(true)
+	res, _, err := client.Notifications.CreateFromTemplate("template-fake-id", target, map[string]interface{}{"name": "Jane"})
+	if err != nil {
+		t.Fatalf("CreateFromTemplate returned an error: %v", err)
+	}
+	if res.ID != "notif-fake-id" {
+		t.Errorf("CreateFromTemplate returned %+v", res)
+	}
+	if target.TemplateID != "" {
+		t.Errorf("CreateFromTemplate should not modify the target request")
+	}
+}
+
+func TestNotificationsService_CreateFromTemplate_noTarget(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("A notification without target should not be sent")
+	})
+
+	_, _, err := client.Notifications.CreateFromTemplateContext(context.Background(), "template-fake-id", nil, nil)
+	if got, want := validationFields(t, err), []string{"included_segments"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateFromTemplateContext validation errors on %v, want %v", got, want)
+	}
+}
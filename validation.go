@@ -0,0 +1,240 @@
+package onesignal
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Maximum number of recipients of an include_* targeting parameter
+	maxIncludeRecipients = 2000
+	// Maximum number of action buttons of a push notification
+	maxButtons = 3
+	// Maximum number of action buttons of a web push notification
+	maxWebButtons = 2
+	// Maximum number of media files attached to a SMS
+	maxSMSMediaURLs = 10
+	// Placeholder that must be included in the body of emails
+	emailUnsubscribeURL = "[unsubscribe_url]"
+)
+
+// ValidationError describes a rule violated by a request.
+type ValidationError struct {
+	// JSON name of the invalid field
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors lists every rule violated by a request.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid request:\n - %s", strings.Join(messages, "\n - "))
+}
+
+func (e *ValidationErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// SetRequestValidation enables validating notification requests
+// with NotificationRequest.Validate before sending them.
+func (c *Client) SetRequestValidation(enabled bool) {
+	c.validateRequests = enabled
+}
+
+// channel returns the message type of the notification
+func (n *NotificationRequest) channel() MessageType {
+	switch {
+	case n.ChannelForExternalUserIDs != "":
+		return n.ChannelForExternalUserIDs
+	case len(n.IncludeEmailTokens) > 0, n.EmailSubject != "", n.EmailBody != "":
+		return MessageTypeEmail
+	case len(n.IncludePhoneNumber) > 0, n.SMSFrom != "", len(n.SMSMediaURLs) > 0:
+		return MessageTypeSMS
+	default:
+		return MessageTypePush
+	}
+}
+
+// namedRecipients is an include_* targeting parameter with its JSON name
+type namedRecipients struct {
+	name string
+	ids  []string
+}
+
+// includedRecipients returns the include_* targeting parameters which are set
+func (n *NotificationRequest) includedRecipients() []namedRecipients {
+	var result []namedRecipients
+	for _, r := range []namedRecipients{
+		{"include_player_ids", n.IncludePlayerIDs},
+		{"include_external_user_ids", n.IncludeExternalUserIDs},
+		{"include_email_tokens", n.IncludeEmailTokens},
+		{"include_phone_numbers", n.IncludePhoneNumber},
+		{"include_ios_tokens", n.IncludeIOSTokens},
+		{"include_android_reg_ids", n.IncludeAndroidRegIDs},
+		{"include_wp_uris", n.IncludeWPURIs},
+		{"include_wp_wns_uris", n.IncludeWPWNSURIs},
+		{"include_amazon_reg_ids", n.IncludeAmazonRegIDs},
+		{"include_chrome_reg_ids", n.IncludeChromeRegIDs},
+		{"include_chrome_web_reg_ids", n.IncludeChromeWebRegIDs},
+	} {
+		if len(r.ids) > 0 {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// Validate checks the notification request against the rules of the OneSignal API:
+// targeting exclusivity, channel specific required fields and size limits.
+// It returns ValidationErrors listing every violated rule, or nil.
+func (n *NotificationRequest) Validate() error {
+	var errs ValidationErrors
+
+	n.validateTargeting(&errs)
+
+	switch channel := n.channel(); channel {
+	case MessageTypePush:
+		n.validatePush(&errs)
+	case MessageTypeEmail:
+		n.validateEmail(&errs)
+	case MessageTypeSMS:
+		n.validateSMS(&errs)
+	default:
+		errs.add("channel_for_external_user_ids", "unknown channel %q", channel)
+	}
+
+	if n.DelayedOption == DelayedOptionTimezone && n.DeliveryTimeOfDay == "" {
+		errs.add("delivery_time_of_day", "is required when delayed_option is %q", DelayedOptionTimezone)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (n *NotificationRequest) validateTargeting(errs *ValidationErrors) {
+	var methods []string
+	if len(n.IncludedSegments) > 0 || len(n.ExcludedSegments) > 0 {
+		methods = append(methods, "segments")
+		if len(n.IncludedSegments) == 0 {
+			errs.add("included_segments", "is required when excluded_segments is set")
+		}
+	}
+	if n.Filters != nil || n.Tags != nil {
+		methods = append(methods, "filters")
+	}
+
+	included := n.includedRecipients()
+	if len(included) > 0 {
+		methods = append(methods, "include_*")
+	}
+	if len(included) > 1 {
+		names := make([]string, len(included))
+		for i, r := range included {
+			names[i] = r.name
+		}
+		errs.add(names[0], "can't be combined with %s", strings.Join(names[1:], ", "))
+	}
+	for _, r := range included {
+		if len(r.ids) > maxIncludeRecipients {
+			errs.add(r.name, "can't have more than %d entries, got %d", maxIncludeRecipients, len(r.ids))
+		}
+	}
+
+	switch {
+	case len(methods) == 0:
+		errs.add("included_segments", "a target is required: included_segments, filters or include_* parameters")
+	case len(methods) > 1:
+		errs.add("included_segments", "%s targeting can't be combined", strings.Join(methods, ", "))
+	}
+
+	switch filters := n.Filters.(type) {
+	case Filters:
+		if err := filters.Validate(); err != nil {
+			errs.add("filters", "%v", err)
+		}
+	case []Filter:
+		if err := Filters(filters).Validate(); err != nil {
+			errs.add("filters", "%v", err)
+		}
+	case []interface{}:
+		if len(filters) > maxFilters {
+			errs.add("filters", "can't have more than %d entries, got %d", maxFilters, len(filters))
+		}
+	case []map[string]interface{}:
+		if len(filters) > maxFilters {
+			errs.add("filters", "can't have more than %d entries, got %d", maxFilters, len(filters))
+		}
+	}
+}
+
+func (n *NotificationRequest) validatePush(errs *ValidationErrors) {
+	if len(n.Contents) == 0 && !n.ContentAvailable && n.TemplateID == "" {
+		errs.add("contents", "is required unless content_available or template_id is set")
+	}
+	for _, texts := range []struct {
+		field string
+		texts map[string]string
+	}{
+		{"contents", n.Contents},
+		{"headings", n.Headings},
+		{"subtitle", n.Subtitle},
+	} {
+		if len(texts.texts) > 0 && texts.texts["en"] == "" {
+			errs.add(texts.field, "must include English (\"en\") text")
+		}
+	}
+
+	if len(n.Buttons) > maxButtons {
+		errs.add("buttons", "can't have more than %d entries, got %d", maxButtons, len(n.Buttons))
+	}
+	if len(n.WebButtons) > maxWebButtons {
+		errs.add("web_buttons", "can't have more than %d entries, got %d", maxWebButtons, len(n.WebButtons))
+	}
+	for i, b := range n.Buttons {
+		if b.ID == "" {
+			errs.add(fmt.Sprintf("buttons[%d].id", i), "is required")
+		}
+	}
+	for i, b := range n.WebButtons {
+		if b.ID == "" {
+			errs.add(fmt.Sprintf("web_buttons[%d].id", i), "is required")
+		}
+	}
+}
+
+func (n *NotificationRequest) validateEmail(errs *ValidationErrors) {
+	if n.TemplateID != "" {
+		return
+	}
+	if n.EmailSubject == "" {
+		errs.add("email_subject", "is required for emails unless template_id is set")
+	}
+	if n.EmailBody == "" {
+		errs.add("email_body", "is required for emails unless template_id is set")
+	} else if !strings.Contains(n.EmailBody, emailUnsubscribeURL) {
+		errs.add("email_body", "must include %s", emailUnsubscribeURL)
+	}
+}
+
+func (n *NotificationRequest) validateSMS(errs *ValidationErrors) {
+	if len(n.Contents) == 0 && n.TemplateID == "" {
+		errs.add("contents", "is required for SMS unless template_id is set")
+	}
+	if len(n.Contents) > 0 && n.Contents["en"] == "" {
+		errs.add("contents", "must include English (\"en\") text")
+	}
+	if len(n.SMSMediaURLs) > maxSMSMediaURLs {
+		errs.add("sms_media_urls", "can't have more than %d entries, got %d", maxSMSMediaURLs, len(n.SMSMediaURLs))
+	}
+}
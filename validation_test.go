@@ -0,0 +1,169 @@
+package onesignal
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// validationFields returns the fields of the validation errors returned by Validate
+func validationFields(t *testing.T, err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate error should be of type ValidationErrors but is %v: %+v", reflect.TypeOf(err), err)
+	}
+
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+	return fields
+}
+
+func TestNotificationRequest_Validate(t *testing.T) {
+	manyIDs := make([]string, maxIncludeRecipients+1)
+	for i := range manyIDs {
+		manyIDs[i] = "id"
+	}
+
+	for name, tc := range map[string]struct {
+		request NotificationRequest
+		want    []string
+	}{
+		"valid push": {
+			request: *sampleNotificationRequest,
+		},
+		"valid email": {
+			request: NotificationRequest{
+				IncludeEmailTokens: []string{"foo@example.com"},
+				EmailSubject:       "Subject",
+				EmailBody:          `<a href="[unsubscribe_url]">Unsubscribe</a>`,
+			},
+		},
+		"valid sms": {
+			request: NotificationRequest{
+				IncludePhoneNumber: []string{"+15555550100"},
+				Contents:           map[string]string{"en": "Hello"},
+			},
+		},
+		"valid template": {
+			request: NotificationRequest{
+				IncludedSegments: []string{"Subscribed Users"},
+				TemplateID:       "template-id",
+			},
+		},
+		"missing target and contents": {
+			request: NotificationRequest{},
+			want:    []string{"included_segments", "contents"},
+		},
+		"missing english content": {
+			request: NotificationRequest{
+				IncludedSegments: []string{"Subscribed Users"},
+				Contents:         map[string]string{"es": "Hola"},
+				Headings:         map[string]string{"es": "Hola"},
+			},
+			want: []string{"contents", "headings"},
+		},
+		"mixed targeting": {
+			request: NotificationRequest{
+				Contents:         map[string]string{"en": "Hello"},
+				IncludedSegments: []string{"Subscribed Users"},
+				IncludePlayerIDs: []string{"id"},
+				Filters:          Filters{FilterCountry("US")},
+			},
+			want: []string{"included_segments"},
+		},
+		"multiple include parameters": {
+			request: NotificationRequest{
+				Contents:               map[string]string{"en": "Hello"},
+				IncludePlayerIDs:       []string{"id"},
+				IncludeExternalUserIDs: []string{"user-id"},
+			},
+			want: []string{"include_player_ids"},
+		},
+		"too many player ids": {
+			request: NotificationRequest{
+				Contents:         map[string]string{"en": "Hello"},
+				IncludePlayerIDs: manyIDs,
+			},
+			want: []string{"include_player_ids"},
+		},
+		"invalid filters": {
+			request: NotificationRequest{
+				Contents: map[string]string{"en": "Hello"},
+				Filters:  Filters{FilterLanguage(FilterRelationGreaterThan, "en")},
+			},
+			want: []string{"filters"},
+		},
+		"email subject without body": {
+			request: NotificationRequest{
+				ChannelForExternalUserIDs: MessageTypeEmail,
+				IncludeExternalUserIDs:    []string{"user-id"},
+				EmailSubject:              "Subject",
+			},
+			want: []string{"email_body"},
+		},
+		"email body without unsubscribe url": {
+			request: NotificationRequest{
+				IncludeEmailTokens: []string{"foo@example.com"},
+				EmailSubject:       "Subject",
+				EmailBody:          "Body",
+			},
+			want: []string{"email_body"},
+		},
+		"sms without contents": {
+			request: NotificationRequest{
+				IncludePhoneNumber: []string{"+15555550100"},
+				SMSMediaURLs:       make([]string, maxSMSMediaURLs+1),
+			},
+			want: []string{"contents", "sms_media_urls"},
+		},
+		"too many buttons": {
+			request: NotificationRequest{
+				IncludedSegments: []string{"Subscribed Users"},
+				Contents:         map[string]string{"en": "Hello"},
+				Buttons:          []NotificationButton{{ID: "1"}, {ID: "2"}, {ID: "3"}, {}},
+			},
+			want: []string{"buttons", "buttons[3].id"},
+		},
+		"timezone delivery without time of day": {
+			request: NotificationRequest{
+				IncludedSegments: []string{"Subscribed Users"},
+				Contents:         map[string]string{"en": "Hello"},
+				DelayedOption:    DelayedOptionTimezone,
+			},
+			want: []string{"delivery_time_of_day"},
+		},
+	} {
+		got := validationFields(t, tc.request.Validate())
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: Validate errors on %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestNotificationsService_Create_validation(t *testing.T) {
+	server, mux, client := setup(t)
+	defer teardown(server)
+
+	client.SetRequestValidation(true)
+
+	requestSent := false
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{})
+	if got, want := validationFields(t, err), []string{"included_segments", "contents"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Create validation errors on %v, want %v", got, want)
+	}
+
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}